@@ -0,0 +1,414 @@
+package proxmox
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const apiClusterAcmePath string = "/api2/json/cluster/acme"
+
+var acmeDomainKeyRegex = regexp.MustCompile(`^acmedomain(\d+)$`)
+
+// AcmeAccountOptions are the parameters for registering an ACME account.
+type AcmeAccountOptions struct {
+	Name      string // Account name, default "default"
+	Contact   string // Required: contact e-mail address
+	Directory string // ACME directory URL, default Let's Encrypt production
+	TosURL    string // URL of the terms of service the account agrees to
+	// External account binding, required by some CAs.
+	EabKid     string
+	EabHmacKey string
+}
+
+func (o *AcmeAccountOptions) validate() error {
+	verr := &ValidationError{Resource: "AcmeAccountOptions"}
+	if o.Contact == "" {
+		verr.Add("contact", "required")
+	}
+	if (o.EabKid == "") != (o.EabHmacKey == "") {
+		verr.Add("eab-kid", "eab-kid and eab-hmac-key must be set together")
+	}
+	return verr.Err()
+}
+
+// AcmeAccount is the decoded response of /cluster/acme/account/{name}.
+type AcmeAccount struct {
+	Account struct {
+		Status    string   `json:"status"`
+		Contact   []string `json:"contact"`
+		CreatedAt string   `json:"createdAt,omitempty"`
+	} `json:"account"`
+	Directory string `json:"directory"`
+	Location  string `json:"location"`
+	Tos       string `json:"tos,omitempty"`
+}
+
+// ListAcmeAccounts returns the names of the registered ACME accounts.
+func (c *Client) ListAcmeAccounts(ctx context.Context) ([]string, error) {
+	var accounts []struct {
+		Name string `json:"name"`
+	}
+	if err := c.do(ctx, http.MethodGet, apiClusterAcmePath+"/account", nil, nil, false, &accounts); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(accounts))
+	for _, a := range accounts {
+		names = append(names, a.Name)
+	}
+	return names, nil
+}
+
+// GetAcmeAccount returns the details of an ACME account.
+func (c *Client) GetAcmeAccount(ctx context.Context, name string) (*AcmeAccount, error) {
+	var account AcmeAccount
+	if err := c.do(ctx, http.MethodGet, apiClusterAcmePath+"/account/"+url.PathEscape(name), nil, nil, false, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// RegisterAcmeAccount registers a new account with the ACME CA.
+func (c *Client) RegisterAcmeAccount(ctx context.Context, opts AcmeAccountOptions) (*Task, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("contact", opts.Contact)
+	for key, v := range map[string]string{
+		"name":         opts.Name,
+		"directory":    opts.Directory,
+		"tos_url":      opts.TosURL,
+		"eab-kid":      opts.EabKid,
+		"eab-hmac-key": opts.EabHmacKey,
+	} {
+		if v != "" {
+			params.Set(key, v)
+		}
+	}
+
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, apiClusterAcmePath+"/account", params, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask("", upid), nil
+}
+
+// UpdateAcmeAccount changes the contact address of an ACME account.
+func (c *Client) UpdateAcmeAccount(ctx context.Context, name, contact string) (*Task, error) {
+	params := url.Values{}
+	params.Set("contact", contact)
+
+	var upid string
+	if err := c.doForm(ctx, http.MethodPut, apiClusterAcmePath+"/account/"+url.PathEscape(name), params, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask("", upid), nil
+}
+
+// DeactivateAcmeAccount deactivates an account at the CA and removes it.
+func (c *Client) DeactivateAcmeAccount(ctx context.Context, name string) (*Task, error) {
+	var upid string
+	if err := c.do(ctx, http.MethodDelete, apiClusterAcmePath+"/account/"+url.PathEscape(name), nil, nil, true, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask("", upid), nil
+}
+
+// AcmePlugin is a challenge plugin from /cluster/acme/plugins.
+type AcmePlugin struct {
+	ID   string `json:"plugin"`
+	Type string `json:"type"`          // "dns" or "standalone"
+	API  string `json:"api,omitempty"` // DNS API, e.g. "cf" or "route53"
+	// Data holds the DNS API credentials and settings, e.g. CF_Token.
+	Data            map[string]string `json:"data,omitempty"`
+	ValidationDelay int               `json:"validation-delay,omitempty"` // seconds
+	Nodes           []string          `json:"nodes,omitempty"`            // Empty for all nodes
+	Disable         bool              `json:"disable,omitempty"`
+}
+
+// acmePluginEntry is an AcmePlugin as PVE returns it.
+type acmePluginEntry struct {
+	Plugin          string      `json:"plugin"`
+	Type            string      `json:"type"`
+	API             string      `json:"api"`
+	Data            string      `json:"data"` // "KEY=value" lines
+	ValidationDelay json.Number `json:"validation-delay"`
+	Nodes           string      `json:"nodes"`
+	Disable         PveBool     `json:"disable"`
+}
+
+func (e *acmePluginEntry) toPlugin() AcmePlugin {
+	p := AcmePlugin{ID: e.Plugin, Type: e.Type, API: e.API, Disable: bool(e.Disable)}
+	p.ValidationDelay = toInt(e.ValidationDelay)
+	p.Nodes = strings.FieldsFunc(e.Nodes, func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
+	for _, line := range strings.Split(e.Data, "\n") {
+		if k, v, ok := strings.Cut(line, "="); ok {
+			if p.Data == nil {
+				p.Data = make(map[string]string)
+			}
+			p.Data[strings.TrimSpace(k)] = v
+		}
+	}
+	return p
+}
+
+func (p *AcmePlugin) validate() error {
+	verr := &ValidationError{Resource: "AcmePlugin"}
+	if p.ID == "" {
+		verr.Add("id", "required")
+	}
+	switch p.Type {
+	case "dns":
+		if p.API == "" {
+			verr.Add("api", "required for dns plugins")
+		}
+	case "standalone":
+		if p.API != "" || len(p.Data) > 0 {
+			verr.Add("api", "standalone plugins take no API or data")
+		}
+	default:
+		verr.Add("type", "must be dns or standalone, got %q", p.Type)
+	}
+	if p.ValidationDelay < 0 || p.ValidationDelay > 172800 {
+		verr.Add("validation-delay", "must be between 0 and 172800, got %d", p.ValidationDelay)
+	}
+	return verr.Err()
+}
+
+func (p *AcmePlugin) toParams(create bool) url.Values {
+	params := url.Values{}
+	if create {
+		params.Set("id", p.ID)
+		params.Set("type", p.Type)
+	}
+	if p.API != "" {
+		params.Set("api", p.API)
+	}
+	if len(p.Data) > 0 {
+		keys := make([]string, 0, len(p.Data))
+		for k := range p.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var data strings.Builder
+		for _, k := range keys {
+			fmt.Fprintf(&data, "%s=%s\n", k, p.Data[k])
+		}
+		params.Set("data", base64.StdEncoding.EncodeToString([]byte(data.String())))
+	}
+	if p.ValidationDelay != 0 {
+		params.Set("validation-delay", fmt.Sprintf("%d", p.ValidationDelay))
+	}
+	if len(p.Nodes) > 0 {
+		params.Set("nodes", strings.Join(p.Nodes, ","))
+	}
+	if p.Disable {
+		params.Set("disable", "1")
+	}
+	return params
+}
+
+// ListAcmePlugins returns the configured ACME challenge plugins.
+func (c *Client) ListAcmePlugins(ctx context.Context) ([]AcmePlugin, error) {
+	var entries []acmePluginEntry
+	if err := c.do(ctx, http.MethodGet, apiClusterAcmePath+"/plugins", nil, nil, false, &entries); err != nil {
+		return nil, err
+	}
+
+	plugins := make([]AcmePlugin, 0, len(entries))
+	for _, e := range entries {
+		plugins = append(plugins, e.toPlugin())
+	}
+	return plugins, nil
+}
+
+// GetAcmePlugin returns a single ACME challenge plugin.
+func (c *Client) GetAcmePlugin(ctx context.Context, id string) (*AcmePlugin, error) {
+	var entry acmePluginEntry
+	if err := c.do(ctx, http.MethodGet, apiClusterAcmePath+"/plugins/"+url.PathEscape(id), nil, nil, false, &entry); err != nil {
+		return nil, err
+	}
+	p := entry.toPlugin()
+	return &p, nil
+}
+
+// CreateAcmePlugin adds an ACME challenge plugin.
+func (c *Client) CreateAcmePlugin(ctx context.Context, plugin *AcmePlugin) error {
+	if plugin == nil {
+		return fmt.Errorf("AcmePlugin is required")
+	}
+	if err := plugin.validate(); err != nil {
+		return err
+	}
+	return c.doForm(ctx, http.MethodPost, apiClusterAcmePath+"/plugins", plugin.toParams(true), nil)
+}
+
+// UpdateAcmePlugin replaces the settings of an existing ACME challenge plugin.
+func (c *Client) UpdateAcmePlugin(ctx context.Context, plugin *AcmePlugin) error {
+	if plugin == nil {
+		return fmt.Errorf("AcmePlugin is required")
+	}
+	if err := plugin.validate(); err != nil {
+		return err
+	}
+	return c.doForm(ctx, http.MethodPut, apiClusterAcmePath+"/plugins/"+url.PathEscape(plugin.ID), plugin.toParams(false), nil)
+}
+
+// DeleteAcmePlugin removes an ACME challenge plugin.
+func (c *Client) DeleteAcmePlugin(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, apiClusterAcmePath+"/plugins/"+url.PathEscape(id), nil, nil, true, nil)
+}
+
+// NodeAcmeDomain is a domain a node orders its ACME certificate for.
+type NodeAcmeDomain struct {
+	Domain string `json:"domain"`
+	Plugin string `json:"plugin,omitempty"` // Empty for the standalone HTTP challenge
+	Alias  string `json:"alias,omitempty"`  // DNS challenge alias domain
+}
+
+// String formats the domain as an acmedomainN property string.
+func (d NodeAcmeDomain) String() string {
+	var b propertyStringBuilder
+	b.str("domain", d.Domain)
+	b.str("plugin", d.Plugin)
+	b.str("alias", d.Alias)
+	return b.String()
+}
+
+// NodeAcmeConfig is the ACME configuration of a node.
+type NodeAcmeConfig struct {
+	Account string           `json:"account,omitempty"` // Default "default"
+	Domains []NodeAcmeDomain `json:"domains"`
+}
+
+// GetNodeAcmeConfig returns the ACME account and domains of node.
+func (c *Client) GetNodeAcmeConfig(ctx context.Context, node string) (*NodeAcmeConfig, error) {
+	var raw map[string]any
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s/config", apiNodesPath, url.PathEscape(node)), nil, nil, false, &raw); err != nil {
+		return nil, err
+	}
+
+	cfg := &NodeAcmeConfig{}
+	if acme, ok := raw["acme"]; ok {
+		props, err := parsePropertyString(fmt.Sprintf("%v", acme), "account")
+		if err != nil {
+			return nil, fmt.Errorf("parsing acme: %w", err)
+		}
+		cfg.Account = props.take("account")
+		// Legacy configs list the domains in the acme property.
+		for _, domain := range strings.Split(props.take("domains"), ";") {
+			if domain != "" {
+				cfg.Domains = append(cfg.Domains, NodeAcmeDomain{Domain: domain})
+			}
+		}
+	}
+
+	indexed := make(map[int]NodeAcmeDomain)
+	for k, v := range raw {
+		i, ok := parseIndexedKey(acmeDomainKeyRegex, k)
+		if !ok {
+			continue
+		}
+		props, err := parsePropertyString(fmt.Sprintf("%v", v), "domain")
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", k, err)
+		}
+		indexed[i] = NodeAcmeDomain{Domain: props.take("domain"), Plugin: props.take("plugin"), Alias: props.take("alias")}
+	}
+	ids := make([]int, 0, len(indexed))
+	for i := range indexed {
+		ids = append(ids, i)
+	}
+	sort.Ints(ids)
+	for _, i := range ids {
+		cfg.Domains = append(cfg.Domains, indexed[i])
+	}
+	return cfg, nil
+}
+
+// maxAcmeDomains is the number of acmedomainN slots PVE supports.
+const maxAcmeDomains = 6
+
+// SetNodeAcmeConfig replaces the ACME account and domains of node. Unused
+// acmedomainN slots are cleared.
+func (c *Client) SetNodeAcmeConfig(ctx context.Context, node string, cfg NodeAcmeConfig) error {
+	verr := &ValidationError{Resource: "NodeAcmeConfig"}
+	if len(cfg.Domains) > maxAcmeDomains {
+		verr.Add("domains", "at most %d domains are supported, got %d", maxAcmeDomains, len(cfg.Domains))
+	}
+	for i, d := range cfg.Domains {
+		if d.Domain == "" {
+			verr.Add(fmt.Sprintf("acmedomain%d", i), "domain is required")
+		}
+	}
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	var unset []string
+	if cfg.Account != "" {
+		params.Set("acme", "account="+cfg.Account)
+	} else {
+		unset = append(unset, "acme")
+	}
+	for i := 0; i < maxAcmeDomains; i++ {
+		key := fmt.Sprintf("acmedomain%d", i)
+		if i < len(cfg.Domains) {
+			params.Set(key, cfg.Domains[i].String())
+		} else {
+			unset = append(unset, key)
+		}
+	}
+	if len(unset) > 0 {
+		params.Set("delete", strings.Join(unset, ","))
+	}
+	return c.doForm(ctx, http.MethodPut, fmt.Sprintf("%s/%s/config", apiNodesPath, url.PathEscape(node)), params, nil)
+}
+
+func (c *Client) nodeAcmeCertificateCommand(ctx context.Context, method, node string, force bool) (*Task, error) {
+	path := fmt.Sprintf("%s/%s/certificates/acme/certificate", apiNodesPath, url.PathEscape(node))
+	params := url.Values{}
+	if force {
+		params.Set("force", "1")
+	}
+
+	var upid string
+	var err error
+	if method == http.MethodDelete {
+		err = c.do(ctx, method, path, nil, nil, true, &upid)
+	} else {
+		err = c.doForm(ctx, method, path, params, &upid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// OrderAcmeCertificate orders a certificate for the domains configured on
+// node and installs it. With force, an existing custom certificate is replaced.
+func (c *Client) OrderAcmeCertificate(ctx context.Context, node string, force bool) (*Task, error) {
+	return c.nodeAcmeCertificateCommand(ctx, http.MethodPost, node, force)
+}
+
+// RenewAcmeCertificate renews the ACME certificate of node. Without force,
+// PVE only renews certificates that expire within 30 days.
+func (c *Client) RenewAcmeCertificate(ctx context.Context, node string, force bool) (*Task, error) {
+	return c.nodeAcmeCertificateCommand(ctx, http.MethodPut, node, force)
+}
+
+// RevokeAcmeCertificate revokes the ACME certificate of node at the CA and
+// removes it.
+func (c *Client) RevokeAcmeCertificate(ctx context.Context, node string) (*Task, error) {
+	return c.nodeAcmeCertificateCommand(ctx, http.MethodDelete, node, false)
+}
@@ -0,0 +1,284 @@
+package proxmox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// kernelPackageRegex matches the kernel packages and meta packages of PVE.
+var kernelPackageRegex = regexp.MustCompile(`^(?:pve|proxmox)-kernel-|^proxmox-default-kernel$`)
+
+// kernelReleaseRegex extracts the release, e.g. "6.8.12-4-pve", from a kernel package.
+var kernelReleaseRegex = regexp.MustCompile(`^(?:pve|proxmox)-kernel-(\d+\.\d+\.\d+-\d+-pve)(?:-signed)?$`)
+
+// AptPackage is a package from /apt/update or /apt/versions.
+type AptPackage struct {
+	Package     string `json:"Package"`
+	Title       string `json:"Title"`
+	Description string `json:"Description,omitempty"`
+	Version     string `json:"Version"`              // Available version
+	OldVersion  string `json:"OldVersion,omitempty"` // Installed version
+	Arch        string `json:"Arch,omitempty"`
+	Origin      string `json:"Origin,omitempty"`
+	Priority    string `json:"Priority,omitempty"`
+	Section     string `json:"Section,omitempty"`
+	// Only set by /apt/versions.
+	CurrentState   string `json:"CurrentState,omitempty"`   // e.g. "Installed" or "NotInstalled"
+	RunningKernel  string `json:"RunningKernel,omitempty"`  // Set on the proxmox-ve entry
+	ManagerVersion string `json:"ManagerVersion,omitempty"` // Set on the pve-manager entry
+}
+
+// IsKernel reports whether the package is a kernel or kernel meta package.
+func (p *AptPackage) IsKernel() bool {
+	return kernelPackageRegex.MatchString(p.Package)
+}
+
+func aptPath(node, sub string) string {
+	return fmt.Sprintf("%s/%s/apt/%s", apiNodesPath, url.PathEscape(node), sub)
+}
+
+// RefreshAptIndex runs apt-get update on node.
+func (c *Client) RefreshAptIndex(ctx context.Context, node string) (*Task, error) {
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, aptPath(node, "update"), url.Values{}, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// ListAptUpdates returns the packages that can be upgraded on node, based on
+// the package index as of the last RefreshAptIndex.
+func (c *Client) ListAptUpdates(ctx context.Context, node string) ([]AptPackage, error) {
+	var pkgs []AptPackage
+	if err := c.do(ctx, http.MethodGet, aptPath(node, "update"), nil, nil, false, &pkgs); err != nil {
+		return nil, err
+	}
+	return pkgs, nil
+}
+
+// GetAptChangelog returns the changelog of a package. An empty version
+// returns the changelog of the candidate version.
+func (c *Client) GetAptChangelog(ctx context.Context, node, pkg, version string) (string, error) {
+	query := url.Values{}
+	query.Set("name", pkg)
+	if version != "" {
+		query.Set("version", version)
+	}
+
+	var changelog string
+	if err := c.do(ctx, http.MethodGet, aptPath(node, "changelog?"+query.Encode()), nil, nil, false, &changelog); err != nil {
+		return "", err
+	}
+	return changelog, nil
+}
+
+// ListAptVersions returns the installed versions of the PVE-relevant packages on node.
+func (c *Client) ListAptVersions(ctx context.Context, node string) ([]AptPackage, error) {
+	var pkgs []AptPackage
+	if err := c.do(ctx, http.MethodGet, aptPath(node, "versions"), nil, nil, false, &pkgs); err != nil {
+		return nil, err
+	}
+	return pkgs, nil
+}
+
+// AptRepositoryOption is an option of a repository entry, e.g. signed-by.
+type AptRepositoryOption struct {
+	Key    string   `json:"Key"`
+	Values []string `json:"Values"`
+}
+
+// AptRepository is a single repository entry of a sources file.
+type AptRepository struct {
+	Types      []string              `json:"Types"` // "deb" and/or "deb-src"
+	URIs       []string              `json:"URIs"`
+	Suites     []string              `json:"Suites"`
+	Components []string              `json:"Components,omitempty"`
+	Options    []AptRepositoryOption `json:"Options,omitempty"`
+	Comment    string                `json:"Comment,omitempty"`
+	FileType   string                `json:"FileType"` // "list" or "sources"
+	Enabled    PveBool               `json:"Enabled"`
+}
+
+// AptRepositoryFile is a parsed APT sources file.
+type AptRepositoryFile struct {
+	Path         string          `json:"path"`
+	FileType     string          `json:"file-type"`
+	Repositories []AptRepository `json:"repositories"`
+}
+
+// AptRepositoryInfo is a note PVE attaches to a repository entry, such as a
+// warning about a suite that does not match the installed release.
+type AptRepositoryInfo struct {
+	Path     string `json:"path"`
+	Index    int    `json:"index"` // Index of the repository within the file
+	Kind     string `json:"kind"`  // e.g. "origin", "warning" or "ignore-pre-upgrade-warning"
+	Message  string `json:"message"`
+	Property string `json:"property,omitempty"`
+}
+
+// AptStandardRepository is one of the standard PVE repositories.
+type AptStandardRepository struct {
+	Handle      string   `json:"handle"` // e.g. "enterprise", "no-subscription", "test"
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Status      *PveBool `json:"status,omitempty"` // nil if not configured
+}
+
+// AptRepositoryStatus is the decoded response of /apt/repositories.
+type AptRepositoryStatus struct {
+	Digest string              `json:"digest"`
+	Files  []AptRepositoryFile `json:"files"`
+	Errors []struct {
+		Path  string `json:"path"`
+		Error string `json:"error"`
+	} `json:"errors,omitempty"`
+	Infos         []AptRepositoryInfo     `json:"infos,omitempty"`
+	StandardRepos []AptStandardRepository `json:"standard-repos"`
+}
+
+// Warnings returns the infos of kind "warning".
+func (s *AptRepositoryStatus) Warnings() []AptRepositoryInfo {
+	var warnings []AptRepositoryInfo
+	for _, info := range s.Infos {
+		if info.Kind == "warning" {
+			warnings = append(warnings, info)
+		}
+	}
+	return warnings
+}
+
+// EnabledStandardRepos returns the handles of the enabled standard repositories.
+func (s *AptRepositoryStatus) EnabledStandardRepos() []string {
+	var enabled []string
+	for _, repo := range s.StandardRepos {
+		if repo.Status != nil && bool(*repo.Status) {
+			enabled = append(enabled, repo.Handle)
+		}
+	}
+	return enabled
+}
+
+// GetAptRepositories returns the APT repository configuration of node.
+func (c *Client) GetAptRepositories(ctx context.Context, node string) (*AptRepositoryStatus, error) {
+	var status AptRepositoryStatus
+	if err := c.do(ctx, http.MethodGet, aptPath(node, "repositories"), nil, nil, false, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// NodeUpdateStatus is the pending update state of a single node.
+type NodeUpdateStatus struct {
+	Node          string       `json:"node"`
+	Updates       []AptPackage `json:"updates,omitempty"`
+	KernelUpdate  bool         `json:"kernelUpdate"`            // A kernel package is among Updates
+	RunningKernel string       `json:"runningKernel,omitempty"` // e.g. "6.8.12-4-pve"
+	NewestKernel  string       `json:"newestKernel,omitempty"`  // Newest installed kernel release
+	// RebootRequired is set if installing the updates brings in a new kernel,
+	// or a newer kernel than the running one is already installed.
+	RebootRequired bool  `json:"rebootRequired"`
+	Err            error `json:"-"`
+}
+
+// newNodeUpdateStatus derives the kernel and reboot state of node from its
+// pending updates and installed package versions.
+func newNodeUpdateStatus(node string, updates, versions []AptPackage) NodeUpdateStatus {
+	s := NodeUpdateStatus{Node: node, Updates: updates}
+	for _, pkg := range updates {
+		if pkg.IsKernel() {
+			s.KernelUpdate = true
+		}
+	}
+	for _, pkg := range versions {
+		if pkg.RunningKernel != "" {
+			s.RunningKernel = pkg.RunningKernel
+		}
+		if m := kernelReleaseRegex.FindStringSubmatch(pkg.Package); m != nil && pkg.CurrentState == "Installed" {
+			if compareNatural(m[1], s.NewestKernel) > 0 {
+				s.NewestKernel = m[1]
+			}
+		}
+	}
+	kernelBehind := s.RunningKernel != "" && compareNatural(s.NewestKernel, s.RunningKernel) > 0
+	s.RebootRequired = s.KernelUpdate || kernelBehind
+	return s
+}
+
+// ClusterUpdateReport is the pending update state of all nodes of a cluster.
+type ClusterUpdateReport struct {
+	Nodes []NodeUpdateStatus `json:"nodes"`
+}
+
+// NodesWithUpdates returns the nodes that have pending updates.
+func (r *ClusterUpdateReport) NodesWithUpdates() []string {
+	var nodes []string
+	for _, n := range r.Nodes {
+		if len(n.Updates) > 0 {
+			nodes = append(nodes, n.Node)
+		}
+	}
+	return nodes
+}
+
+// NodesNeedingReboot returns the nodes that need a reboot to run their
+// newest kernel, either now or after installing their updates.
+func (r *ClusterUpdateReport) NodesNeedingReboot() []string {
+	var nodes []string
+	for _, n := range r.Nodes {
+		if n.RebootRequired {
+			nodes = append(nodes, n.Node)
+		}
+	}
+	return nodes
+}
+
+// GetClusterUpdateReport concurrently collects the pending updates of every
+// node. Nodes that are offline or fail to answer are included with Err set,
+// and their errors are returned joined together with the partial report.
+func (c *Client) GetClusterUpdateReport(ctx context.Context) (*ClusterUpdateReport, error) {
+	nodes, err := c.ListNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Node < nodes[j].Node })
+
+	report := &ClusterUpdateReport{Nodes: make([]NodeUpdateStatus, len(nodes))}
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		if n.Status != "online" {
+			report.Nodes[i] = NodeUpdateStatus{Node: n.Node, Err: fmt.Errorf("node is %s", n.Status)}
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			updates, err := c.ListAptUpdates(ctx, n.Node)
+			if err != nil {
+				report.Nodes[i] = NodeUpdateStatus{Node: n.Node, Err: err}
+				return
+			}
+			versions, err := c.ListAptVersions(ctx, n.Node)
+			if err != nil {
+				report.Nodes[i] = newNodeUpdateStatus(n.Node, updates, nil)
+				report.Nodes[i].Err = err
+				return
+			}
+			report.Nodes[i] = newNodeUpdateStatus(n.Node, updates, versions)
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, n := range report.Nodes {
+		if n.Err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", n.Node, n.Err))
+		}
+	}
+	return report, errors.Join(errs...)
+}
@@ -0,0 +1,408 @@
+package proxmox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const apiClusterBackupPath string = "/api2/json/cluster/backup"
+
+// BackupMode selects how vzdump quiesces a guest.
+type BackupMode string
+
+const (
+	BackupModeSnapshot BackupMode = "snapshot"
+	BackupModeSuspend  BackupMode = "suspend"
+	BackupModeStop     BackupMode = "stop"
+)
+
+// BackupMailNotification selects when vzdump sends mail.
+type BackupMailNotification string
+
+const (
+	BackupMailAlways  BackupMailNotification = "always"
+	BackupMailFailure BackupMailNotification = "failure"
+)
+
+// BackupFleecing configures backup fleecing, which buffers guest writes on a
+// fast storage while a slow backup target is being written.
+type BackupFleecing struct {
+	Enabled bool
+	Storage string
+}
+
+// ParseBackupFleecing parses a fleecing property string such as
+// "enabled=1,storage=local-lvm".
+func ParseBackupFleecing(s string) (*BackupFleecing, error) {
+	props, err := parsePropertyString(s, "enabled")
+	if err != nil {
+		return nil, fmt.Errorf("parsing fleecing: %w", err)
+	}
+	enabled, err := props.takeBool("enabled")
+	if err != nil {
+		return nil, fmt.Errorf("parsing fleecing: %w", err)
+	}
+	return &BackupFleecing{Enabled: enabled != nil && *enabled, Storage: props.take("storage")}, nil
+}
+
+// String formats the fleecing settings as a PVE property string.
+func (f *BackupFleecing) String() string {
+	var b propertyStringBuilder
+	b.bool("enabled", &f.Enabled)
+	b.str("storage", f.Storage)
+	return b.String()
+}
+
+// BackupOptions are the vzdump parameters shared by one-off backups and
+// scheduled backup jobs.
+type BackupOptions struct {
+	VmIds            []int        // Guests to back up; mutually exclusive with All
+	All              bool         // Back up every guest on the node (or cluster, for jobs)
+	Exclude          []int        // Guests to skip when All is set
+	Pool             string       // Back up every guest in this pool
+	Mode             BackupMode   // PVE defaults to snapshot
+	Storage          string       // Target storage
+	Compress         string       // "0", "1", "gzip", "lzo" or "zstd"
+	NotesTemplate    string       // e.g. "{{guestname}} {{cluster}}"
+	Protected        bool         // Mark the backup as protected from pruning
+	PruneBackups     *PrunePolicy // Retention override for this backup
+	BwLimit          int          // KiB/s
+	MailTo           []string
+	MailNotification BackupMailNotification
+	Fleecing         *BackupFleecing
+	Remove           *bool // Prune older backups according to retention after this backup
+}
+
+func (o *BackupOptions) validate(verr *ValidationError) {
+	selectors := 0
+	for _, set := range []bool{len(o.VmIds) > 0, o.All, o.Pool != ""} {
+		if set {
+			selectors++
+		}
+	}
+	if selectors != 1 {
+		verr.Add("vmid", "exactly one of vmids, all or pool must be set")
+	}
+	if len(o.Exclude) > 0 && !o.All {
+		verr.Add("exclude", "only allowed together with all")
+	}
+	switch o.Mode {
+	case "", BackupModeSnapshot, BackupModeSuspend, BackupModeStop:
+	default:
+		verr.Add("mode", "unknown backup mode %q", o.Mode)
+	}
+	switch o.Compress {
+	case "", "0", "1", "gzip", "lzo", "zstd":
+	default:
+		verr.Add("compress", "unknown compression %q", o.Compress)
+	}
+	if o.Fleecing != nil && o.Fleecing.Enabled && o.Fleecing.Storage == "" {
+		verr.Add("fleecing", "storage required when fleecing is enabled")
+	}
+	if o.PruneBackups != nil {
+		o.PruneBackups.validate(verr)
+	}
+}
+
+func (o *BackupOptions) encode(params url.Values) {
+	if len(o.VmIds) > 0 {
+		params.Set("vmid", joinVmIds(o.VmIds))
+	}
+	if o.All {
+		params.Set("all", "1")
+	}
+	if len(o.Exclude) > 0 {
+		params.Set("exclude", joinVmIds(o.Exclude))
+	}
+	if o.Pool != "" {
+		params.Set("pool", o.Pool)
+	}
+	if o.Mode != "" {
+		params.Set("mode", string(o.Mode))
+	}
+	if o.Storage != "" {
+		params.Set("storage", o.Storage)
+	}
+	if o.Compress != "" {
+		params.Set("compress", o.Compress)
+	}
+	if o.NotesTemplate != "" {
+		params.Set("notes-template", o.NotesTemplate)
+	}
+	if o.Protected {
+		params.Set("protected", "1")
+	}
+	if o.PruneBackups != nil {
+		params.Set("prune-backups", o.PruneBackups.String())
+	}
+	if o.BwLimit != 0 {
+		params.Set("bwlimit", fmt.Sprintf("%d", o.BwLimit))
+	}
+	if len(o.MailTo) > 0 {
+		params.Set("mailto", strings.Join(o.MailTo, ","))
+	}
+	if o.MailNotification != "" {
+		params.Set("mailnotification", string(o.MailNotification))
+	}
+	if o.Fleecing != nil {
+		params.Set("fleecing", o.Fleecing.String())
+	}
+	if o.Remove != nil {
+		params.Set("remove", formatPveBool(*o.Remove))
+	}
+}
+
+// decode sets the option for vzdump key k from a raw API value, reporting
+// whether k is a vzdump option.
+func (o *BackupOptions) decode(k string, v any) (bool, error) {
+	s := propertyValue(v)
+	switch k {
+	case "vmid":
+		o.VmIds = splitVmIds(s)
+	case "all":
+		o.All = toInt(v) == 1
+	case "exclude":
+		o.Exclude = splitVmIds(s)
+	case "pool":
+		o.Pool = s
+	case "mode":
+		o.Mode = BackupMode(s)
+	case "storage":
+		o.Storage = s
+	case "compress":
+		o.Compress = s
+	case "notes-template":
+		o.NotesTemplate = s
+	case "protected":
+		o.Protected = toInt(v) == 1
+	case "prune-backups":
+		p, err := ParsePrunePolicy(s)
+		if err != nil {
+			return true, err
+		}
+		o.PruneBackups = p
+	case "bwlimit":
+		o.BwLimit = toInt(v)
+	case "mailto":
+		o.MailTo = strings.Split(s, ",")
+	case "mailnotification":
+		o.MailNotification = BackupMailNotification(s)
+	case "fleecing":
+		f, err := ParseBackupFleecing(s)
+		if err != nil {
+			return true, err
+		}
+		o.Fleecing = f
+	case "remove":
+		remove := toInt(v) == 1
+		o.Remove = &remove
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func joinVmIds(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
+
+func splitVmIds(s string) []int {
+	var ids []int
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == ';' }) {
+		if id, err := strconv.Atoi(part); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// propertyValue returns v as a string. Property strings that PVE returns
+// already decoded into an object are re-encoded as "key=value,..." in key order.
+func propertyValue(v any) string {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Sprintf("%v", v)
+	}
+	opts := make(map[string]string, len(m))
+	for k, val := range m {
+		opts[k] = fmt.Sprintf("%v", val)
+	}
+	var b propertyStringBuilder
+	b.extra(opts)
+	return b.String()
+}
+
+// Backup starts a vzdump backup on node.
+func (c *Client) Backup(ctx context.Context, node string, opts BackupOptions) (*Task, error) {
+	verr := &ValidationError{Resource: "BackupOptions"}
+	opts.validate(verr)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	opts.encode(params)
+
+	path := fmt.Sprintf("%s/%s/vzdump", apiNodesPath, url.PathEscape(node))
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, path, params, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// BackupJob is a scheduled backup job from /cluster/backup.
+type BackupJob struct {
+	ID       string // Assigned by PVE on create if empty
+	Schedule string // systemd calendar event, e.g. "sun 01:00"
+	Enabled  *bool  // PVE enables jobs by default
+	Comment  string
+	Node     string // Only run on this node; empty means every node
+	BackupOptions
+	// Raw holds additional job fields not mapped above, and the raw value of
+	// options that could not be parsed.
+	Raw map[string]string
+	// Err reports the options that could not be parsed.
+	Err error
+}
+
+// backupJobReadOnlyKeys are returned by /cluster/backup but cannot be set.
+var backupJobReadOnlyKeys = map[string]bool{
+	"type":     true,
+	"next-run": true,
+}
+
+// backupJobOptionKeys are the optional job settings UpdateBackupJob manages.
+var backupJobOptionKeys = []string{
+	"vmid", "all", "exclude", "pool", "mode", "storage", "compress",
+	"notes-template", "protected", "prune-backups", "bwlimit", "mailto",
+	"mailnotification", "fleecing", "remove", "comment", "node",
+}
+
+// ParseBackupJob maps a raw /cluster/backup entry onto a BackupJob.
+func ParseBackupJob(raw map[string]any) *BackupJob {
+	job := &BackupJob{Enabled: BoolPtr(true), Raw: make(map[string]string)}
+	for k, v := range raw {
+		switch k {
+		case "id":
+			job.ID = fmt.Sprintf("%v", v)
+		case "schedule":
+			job.Schedule = fmt.Sprintf("%v", v)
+		case "enabled":
+			job.Enabled = BoolPtr(toInt(v) == 1)
+		case "comment":
+			job.Comment = fmt.Sprintf("%v", v)
+		case "node":
+			job.Node = fmt.Sprintf("%v", v)
+		default:
+			known, err := job.BackupOptions.decode(k, v)
+			if err != nil {
+				job.Err = errors.Join(job.Err, fmt.Errorf("%s: %w", k, err))
+			}
+			if !known || err != nil {
+				job.Raw[k] = propertyValue(v)
+			}
+		}
+	}
+	return job
+}
+
+// IsEnabled reports whether the job runs on its schedule.
+func (j *BackupJob) IsEnabled() bool {
+	return j.Enabled == nil || *j.Enabled
+}
+
+func (j *BackupJob) validate() error {
+	verr := &ValidationError{Resource: "BackupJob"}
+	if j.Schedule == "" {
+		verr.Add("schedule", "required")
+	}
+	j.BackupOptions.validate(verr)
+	return verr.Err()
+}
+
+func (j *BackupJob) toParams(create bool) url.Values {
+	params := url.Values{}
+	if create && j.ID != "" {
+		params.Set("id", j.ID)
+	}
+	params.Set("schedule", j.Schedule)
+	if j.Enabled != nil {
+		params.Set("enabled", formatPveBool(*j.Enabled))
+	}
+	if j.Comment != "" {
+		params.Set("comment", j.Comment)
+	}
+	if j.Node != "" {
+		params.Set("node", j.Node)
+	}
+	j.BackupOptions.encode(params)
+	for k, v := range j.Raw {
+		if _, set := params[k]; !set && v != "" && !backupJobReadOnlyKeys[k] {
+			params.Set(k, v)
+		}
+	}
+	if !create {
+		deleteUnset(params, backupJobOptionKeys, nil)
+	}
+	return params
+}
+
+// ListBackupJobs returns the scheduled backup jobs of the cluster.
+func (c *Client) ListBackupJobs(ctx context.Context) ([]BackupJob, error) {
+	var raw []map[string]any
+	if err := c.do(ctx, http.MethodGet, apiClusterBackupPath, nil, nil, false, &raw); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]BackupJob, 0, len(raw))
+	for _, r := range raw {
+		jobs = append(jobs, *ParseBackupJob(r))
+	}
+	return jobs, nil
+}
+
+// GetBackupJob returns the scheduled backup job with the given ID.
+func (c *Client) GetBackupJob(ctx context.Context, id string) (*BackupJob, error) {
+	var raw map[string]any
+	if err := c.do(ctx, http.MethodGet, apiClusterBackupPath+"/"+url.PathEscape(id), nil, nil, false, &raw); err != nil {
+		return nil, err
+	}
+	return ParseBackupJob(raw), nil
+}
+
+// CreateBackupJob adds a scheduled backup job.
+func (c *Client) CreateBackupJob(ctx context.Context, job *BackupJob) error {
+	if job == nil {
+		return fmt.Errorf("BackupJob cannot be nil")
+	}
+	if err := job.validate(); err != nil {
+		return err
+	}
+	return c.doForm(ctx, http.MethodPost, apiClusterBackupPath, job.toParams(true), nil)
+}
+
+// UpdateBackupJob replaces the settings of the scheduled backup job job.ID.
+func (c *Client) UpdateBackupJob(ctx context.Context, job *BackupJob) error {
+	if job == nil || job.ID == "" {
+		return fmt.Errorf("BackupJob with an ID is required")
+	}
+	if err := job.validate(); err != nil {
+		return err
+	}
+	return c.doForm(ctx, http.MethodPut, apiClusterBackupPath+"/"+url.PathEscape(job.ID), job.toParams(false), nil)
+}
+
+// DeleteBackupJob removes a scheduled backup job.
+func (c *Client) DeleteBackupJob(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, apiClusterBackupPath+"/"+url.PathEscape(id), nil, nil, true, nil)
+}
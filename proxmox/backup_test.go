@@ -0,0 +1,47 @@
+package proxmox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBackupJobEnabledDefault(t *testing.T) {
+	job := &BackupJob{Schedule: "sun 01:00"}
+	if _, set := job.toParams(true)["enabled"]; set {
+		t.Error("enabled sent for a job that did not set it")
+	}
+	if !job.IsEnabled() {
+		t.Error("IsEnabled() = false for a job without Enabled")
+	}
+
+	parsed := ParseBackupJob(map[string]any{"id": "backup-1", "schedule": "sun 01:00"})
+	if !parsed.IsEnabled() {
+		t.Error("parsed job without enabled is not enabled")
+	}
+	if got := parsed.toParams(false).Get("enabled"); got != "1" {
+		t.Errorf("parsed job sends enabled=%q, want 1", got)
+	}
+
+	disabled := &BackupJob{Schedule: "sun 01:00", Enabled: BoolPtr(false)}
+	if got := disabled.toParams(true).Get("enabled"); got != "0" {
+		t.Errorf("disabled job sends enabled=%q, want 0", got)
+	}
+}
+
+func TestParseBackupJobInvalidPrunePolicy(t *testing.T) {
+	job := ParseBackupJob(map[string]any{"id": "backup-1", "schedule": "sun 01:00", "all": 1, "prune-backups": "keep-last=1,keep-future=2"})
+	if job.Err == nil {
+		t.Error("Err not set for an invalid prune-backups")
+	}
+	if job.PruneBackups != nil {
+		t.Errorf("PruneBackups = %+v, want nil", job.PruneBackups)
+	}
+	// The raw value is sent back unchanged instead of being deleted.
+	params := job.toParams(false)
+	if got := params.Get("prune-backups"); got != "keep-last=1,keep-future=2" {
+		t.Errorf("update sends prune-backups=%q", got)
+	}
+	if strings.Contains(params.Get("delete"), "prune-backups") {
+		t.Errorf("update deletes prune-backups: %q", params.Get("delete"))
+	}
+}
@@ -0,0 +1,217 @@
+package proxmox
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CertificateExpiryWarning is the remaining validity below which a
+// certificate is considered about to expire.
+const CertificateExpiryWarning = 30 * 24 * time.Hour
+
+// Certificate files PVE serves the API and web UI with.
+const (
+	CertFileProxy = "pveproxy-ssl.pem" // Custom or ACME certificate, if configured
+	CertFileNode  = "pve-ssl.pem"      // Certificate issued by the cluster CA
+)
+
+// NodeCertificate is a certificate from /nodes/{node}/certificates/info.
+type NodeCertificate struct {
+	Filename      string   `json:"filename"`
+	Fingerprint   string   `json:"fingerprint"` // SHA-256, colon-separated hex
+	Issuer        string   `json:"issuer"`
+	Subject       string   `json:"subject"`
+	SANs          []string `json:"san,omitempty"`
+	NotBefore     int64    `json:"notbefore"` // Unix time
+	NotAfter      int64    `json:"notafter"`  // Unix time
+	PublicKeyType string   `json:"public-key-type,omitempty"`
+	PublicKeyBits int      `json:"public-key-bits,omitempty"`
+	PEM           string   `json:"pem,omitempty"`
+}
+
+// Expires returns the end of the certificate's validity.
+func (c *NodeCertificate) Expires() time.Time {
+	return time.Unix(c.NotAfter, 0)
+}
+
+// ExpiresWithin reports whether the certificate expires within d of now, or
+// has already expired.
+func (c *NodeCertificate) ExpiresWithin(d time.Duration, now time.Time) bool {
+	return c.Expires().Before(now.Add(d))
+}
+
+// ServedCertificate returns the certificate pveproxy serves: the custom or
+// ACME certificate if there is one, otherwise the cluster-issued one.
+func ServedCertificate(certs []NodeCertificate) *NodeCertificate {
+	var served *NodeCertificate
+	for i := range certs {
+		switch certs[i].Filename {
+		case CertFileProxy:
+			return &certs[i]
+		case CertFileNode:
+			served = &certs[i]
+		}
+	}
+	return served
+}
+
+// ListNodeCertificates returns the certificates of node.
+func (c *Client) ListNodeCertificates(ctx context.Context, node string) ([]NodeCertificate, error) {
+	path := fmt.Sprintf("%s/%s/certificates/info", apiNodesPath, url.PathEscape(node))
+
+	var certs []NodeCertificate
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &certs); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+// CustomCertificateOptions are the parameters for uploading a custom API certificate.
+type CustomCertificateOptions struct {
+	Certificates string // Required: PEM certificate chain, leaf first
+	Key          string // PEM private key; may be omitted to keep the existing key
+	Force        bool   // Overwrite an existing custom certificate
+	Restart      bool   // Restart pveproxy to serve the new certificate
+}
+
+func (o *CustomCertificateOptions) validate() error {
+	verr := &ValidationError{Resource: "CustomCertificateOptions"}
+	if !strings.Contains(o.Certificates, "-----BEGIN CERTIFICATE-----") {
+		verr.Add("certificates", "must contain a PEM certificate")
+	}
+	if o.Key != "" && !strings.Contains(o.Key, "PRIVATE KEY-----") {
+		verr.Add("key", "must be a PEM private key")
+	}
+	return verr.Err()
+}
+
+// UploadCustomCertificate installs a custom certificate for the API and web
+// UI of node and returns the installed certificate.
+func (c *Client) UploadCustomCertificate(ctx context.Context, node string, opts CustomCertificateOptions) (*NodeCertificate, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("certificates", opts.Certificates)
+	if opts.Key != "" {
+		params.Set("key", opts.Key)
+	}
+	if opts.Force {
+		params.Set("force", "1")
+	}
+	if opts.Restart {
+		params.Set("restart", "1")
+	}
+
+	path := fmt.Sprintf("%s/%s/certificates/custom", apiNodesPath, url.PathEscape(node))
+	var cert NodeCertificate
+	if err := c.doForm(ctx, http.MethodPost, path, params, &cert); err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// DeleteCustomCertificate removes the custom certificate of node, reverting
+// to the cluster-issued one. With restart, pveproxy is restarted to apply it.
+func (c *Client) DeleteCustomCertificate(ctx context.Context, node string, restart bool) error {
+	path := fmt.Sprintf("%s/%s/certificates/custom", apiNodesPath, url.PathEscape(node))
+	if restart {
+		path += "?restart=1"
+	}
+	return c.do(ctx, http.MethodDelete, path, nil, nil, true, nil)
+}
+
+// normalizeFingerprint converts a fingerprint in any common notation
+// ("AB:CD:..", "abcd..") to lower-case hex without separators.
+func normalizeFingerprint(fp string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fp))
+}
+
+// pinnedTLSConfig returns a TLS config that accepts only server certificates
+// with one of the given SHA-256 fingerprints.
+func pinnedTLSConfig(fingerprints []string) (*tls.Config, error) {
+	if len(fingerprints) == 0 {
+		return nil, errors.New("at least one fingerprint is required")
+	}
+	pinned := make(map[string]bool, len(fingerprints))
+	for _, fp := range fingerprints {
+		n := normalizeFingerprint(fp)
+		if len(n) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid SHA-256 fingerprint %q", fp)
+		}
+		pinned[n] = true
+	}
+
+	return &tls.Config{
+		// The pin replaces chain verification, which self-signed PVE certificates fail.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("server sent no certificate")
+			}
+			sum := sha256.Sum256(rawCerts[0])
+			if !pinned[hex.EncodeToString(sum[:])] {
+				return fmt.Errorf("server certificate fingerprint %x is not pinned", sum)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// ExpiringCertificate is a certificate of a node that expires soon.
+type ExpiringCertificate struct {
+	Node        string          `json:"node"`
+	Certificate NodeCertificate `json:"certificate"`
+}
+
+// FindExpiringCertificates concurrently collects the certificates of all online
+// nodes that expire within d, e.g. CertificateExpiryWarning. Nodes that fail
+// to answer are skipped and their errors returned joined with the result.
+func (c *Client) FindExpiringCertificates(ctx context.Context, d time.Duration) ([]ExpiringCertificate, error) {
+	nodes, err := c.ListNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Node < nodes[j].Node })
+
+	certs := make([][]NodeCertificate, len(nodes))
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		if n.Status != "online" {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if certs[i], errs[i] = c.ListNodeCertificates(ctx, n.Node); errs[i] != nil {
+				errs[i] = fmt.Errorf("node %s: %w", n.Node, errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	now := time.Now()
+	var expiring []ExpiringCertificate
+	for i, n := range nodes {
+		for _, cert := range certs[i] {
+			if cert.ExpiresWithin(d, now) {
+				expiring = append(expiring, ExpiringCertificate{Node: n.Node, Certificate: cert})
+			}
+		}
+	}
+	return expiring, errors.Join(errs...)
+}
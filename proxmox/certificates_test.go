@@ -0,0 +1,72 @@
+package proxmox
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientPinned(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[]}`)
+	}))
+	defer srv.Close()
+	sum := sha256.Sum256(srv.Certificate().Raw)
+	fingerprint := strings.ToUpper(fmt.Sprintf("% x", sum[:]))
+	fingerprint = strings.ReplaceAll(fingerprint, " ", ":")
+
+	c, err := NewClientPinned(srv.URL, "root@pam!ci", "secret", true, fingerprint)
+	if err != nil {
+		t.Fatalf("NewClientPinned: %v", err)
+	}
+	if _, err := c.ListNodes(context.Background()); err != nil {
+		t.Errorf("pinned certificate rejected: %v", err)
+	}
+
+	other := strings.Repeat("00", sha256.Size)
+	c, err = NewClientPinned(srv.URL, "root@pam!ci", "secret", true, other)
+	if err != nil {
+		t.Fatalf("NewClientPinned: %v", err)
+	}
+	if _, err := c.ListNodes(context.Background()); err == nil {
+		t.Error("unpinned certificate accepted")
+	}
+
+	if _, err := NewClientPinned(srv.URL, "root@pam!ci", "secret", true, "abcd"); err == nil {
+		t.Error("short fingerprint accepted")
+	}
+}
+
+func TestFindExpiringCertificates(t *testing.T) {
+	soon := time.Now().Add(24 * time.Hour).Unix()
+	later := time.Now().Add(365 * 24 * time.Hour).Unix()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiNodesPath:
+			fmt.Fprint(w, `{"data":[{"node":"pve2","status":"online"},{"node":"pve1","status":"online"},{"node":"pve3","status":"offline"},{"node":"pve4","status":"online"}]}`)
+		case apiNodesPath + "/pve1/certificates/info":
+			fmt.Fprintf(w, `{"data":[{"filename":"pve-ssl.pem","notafter":%d},{"filename":"pveproxy-ssl.pem","notafter":%d}]}`, soon, later)
+		case apiNodesPath + "/pve2/certificates/info":
+			fmt.Fprintf(w, `{"data":[{"filename":"pve-ssl.pem","notafter":%d}]}`, soon)
+		default:
+			http.Error(w, "unreachable", http.StatusInternalServerError)
+		}
+	})
+
+	expiring, err := c.FindExpiringCertificates(context.Background(), CertificateExpiryWarning)
+	if err == nil || !strings.Contains(err.Error(), "node pve4") || strings.Contains(err.Error(), "pve3") {
+		t.Errorf("error = %v, want only pve4 to fail", err)
+	}
+	var got []string
+	for _, e := range expiring {
+		got = append(got, e.Node+"/"+e.Certificate.Filename)
+	}
+	if want := "pve1/pve-ssl.pem,pve2/pve-ssl.pem"; strings.Join(got, ",") != want {
+		t.Errorf("expiring = %v, want %s", got, want)
+	}
+}
@@ -79,7 +79,28 @@ func NewClientToken(base, tokenID, secret string, tlsCfg bool) (*Client, error)
 	return newClient(base, tokenID, secret, AuthToken, true)
 }
 
+// NewClientPinned creates a client that only accepts server certificates
+// whose SHA-256 fingerprint is one of fingerprints, e.g.
+// NodeCertificate.Fingerprint, instead of skipping verification.
+func NewClientPinned(base, username, password string, useToken bool, fingerprints ...string) (*Client, error) {
+	authMethod := AuthPassword
+	if useToken {
+		authMethod = AuthToken
+	}
+	tlsConfig, err := pinnedTLSConfig(fingerprints)
+	if err != nil {
+		return nil, err
+	}
+	return newClientTLS(base, username, password, authMethod, tlsConfig)
+}
+
 func newClient(base, username, password string, method AuthMethod, ignoreTlsError bool) (*Client, error) {
+	return newClientTLS(base, username, password, method, &tls.Config{
+		InsecureSkipVerify: ignoreTlsError,
+	})
+}
+
+func newClientTLS(base, username, password string, method AuthMethod, tlsConfig *tls.Config) (*Client, error) {
 	if base == "" {
 		return nil, errors.New("base URL required")
 	}
@@ -88,12 +109,7 @@ func newClient(base, username, password string, method AuthMethod, ignoreTlsErro
 		return nil, fmt.Errorf("invalid base URL: %w", err)
 	}
 
-	// Custom transport to optionally skip TLS verification
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: ignoreTlsError,
-		},
-	}
+	tr := &http.Transport{TLSClientConfig: tlsConfig}
 
 	return &Client{
 		baseURL:    u,
@@ -176,12 +192,54 @@ func (c *Client) Login(ctx context.Context) error {
 }
 
 func (c *Client) do(ctx context.Context, method, path string, body io.Reader, headers map[string]string, csrf bool, out any) error {
+	req, err := c.newRequest(ctx, method, path, body, headers, csrf)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return decodeResponse(resp, out)
+}
+
+// doAttribs is do for calls that return result attributes, such as
+// "changes", next to "data". The whole response object is decoded into attribs.
+func (c *Client) doAttribs(ctx context.Context, method, path string, out, attribs any) error {
+	req, err := c.newRequest(ctx, method, path, nil, nil, method != http.MethodGet)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return decodeResponseAttribs(resp, out, attribs)
+}
+
+// newRequest builds an authenticated API request for path.
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader, headers map[string]string, csrf bool) (*http.Request, error) {
+	// path is already escaped (segments go through url.PathEscape), so keep it as
+	// RawPath to preserve escaped slashes in volume IDs.
 	full := *c.baseURL
-	full.Path = strings.TrimRight(c.baseURL.Path, "/") + path
+	path, rawQuery, _ := strings.Cut(path, "?")
+	full.RawPath = strings.TrimRight(c.baseURL.EscapedPath(), "/") + path
+	unescaped, err := url.PathUnescape(full.RawPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request path %q: %w", path, err)
+	}
+	full.Path = unescaped
+	full.RawQuery = rawQuery
 
 	req, err := http.NewRequestWithContext(ctx, method, full.String(), body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Authentication
@@ -208,13 +266,19 @@ func (c *Client) do(ctx context.Context, method, path string, body io.Reader, he
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
+	return req, nil
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+// decodeResponse converts an error status to *APIError, otherwise decodes the
+// "data" member of the response into out.
+func decodeResponse(resp *http.Response, out any) error {
+	return decodeResponseAttribs(resp, out, nil)
+}
 
+// decodeResponseAttribs is decodeResponse that also decodes the whole response
+// object into attribs, for calls that return result attributes such as
+// "changes" next to "data".
+func decodeResponseAttribs(resp *http.Response, out, attribs any) error {
 	// Read entire body first
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -230,6 +294,12 @@ func (c *Client) do(ctx context.Context, method, path string, body io.Reader, he
 		return &APIError{Status: resp.StatusCode, Errors: wrapper.Errors}
 	}
 
+	if attribs != nil && len(bodyBytes) > 0 {
+		if err := json.Unmarshal(bodyBytes, attribs); err != nil {
+			return fmt.Errorf("invalid JSON response: %w", err)
+		}
+	}
+
 	if out != nil && len(bodyBytes) > 0 {
 		var wrapper struct {
 			Data json.RawMessage `json:"data"`
@@ -246,3 +316,9 @@ func (c *Client) do(ctx context.Context, method, path string, body io.Reader, he
 
 	return nil
 }
+
+// doForm sends params as an application/x-www-form-urlencoded request body.
+func (c *Client) doForm(ctx context.Context, method, path string, params url.Values, out any) error {
+	headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+	return c.do(ctx, method, path, strings.NewReader(params.Encode()), headers, true, out)
+}
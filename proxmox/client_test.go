@@ -0,0 +1,20 @@
+package proxmox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient returns a token-auth client that talks to handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := newClient(srv.URL, "root@pam!ci", "secret", AuthToken, true)
+	if err != nil {
+		t.Fatalf("newClient: %v", err)
+	}
+	return c
+}
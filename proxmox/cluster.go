@@ -0,0 +1,199 @@
+package proxmox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+)
+
+const apiClusterPath string = "/api2/json/cluster"
+
+var corosyncLinkKeyRegex = regexp.MustCompile(`^(?:ring|link)(\d+)(?:_addr)?$`)
+
+// ClusterNodeStatus is the membership state of a node from /cluster/status.
+type ClusterNodeStatus struct {
+	Name   string `json:"name"`
+	NodeID int    `json:"nodeid"`
+	IP     string `json:"ip"`
+	Online bool   `json:"online"`
+	Local  bool   `json:"local"` // The node that answered the request
+	Level  string `json:"level,omitempty"`
+}
+
+// ClusterStatus is the decoded response of /cluster/status.
+type ClusterStatus struct {
+	Name       string              `json:"name"`
+	Version    int                 `json:"version"` // corosync config version
+	Quorate    bool                `json:"quorate"`
+	NodeCount  int                 `json:"nodeCount"`
+	Standalone bool                `json:"standalone"` // Node is not part of a cluster
+	Nodes      []ClusterNodeStatus `json:"nodes"`
+}
+
+// OnlineNodes returns the names of the nodes that are online.
+func (s *ClusterStatus) OnlineNodes() []string {
+	var online []string
+	for _, n := range s.Nodes {
+		if n.Online {
+			online = append(online, n.Name)
+		}
+	}
+	return online
+}
+
+// clusterStatusEntry is a single entry of /cluster/status as PVE returns it.
+type clusterStatusEntry struct {
+	Type    string  `json:"type"` // "cluster" or "node"
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Version int     `json:"version"`
+	Quorate PveBool `json:"quorate"`
+	Nodes   int     `json:"nodes"`
+	NodeID  int     `json:"nodeid"`
+	IP      string  `json:"ip"`
+	Online  PveBool `json:"online"`
+	Local   PveBool `json:"local"`
+	Level   string  `json:"level"`
+}
+
+// GetClusterStatus returns the cluster name, quorum state and node membership.
+func (c *Client) GetClusterStatus(ctx context.Context) (*ClusterStatus, error) {
+	var entries []clusterStatusEntry
+	if err := c.do(ctx, http.MethodGet, apiClusterPath+"/status", nil, nil, false, &entries); err != nil {
+		return nil, err
+	}
+
+	status := &ClusterStatus{Standalone: true, Quorate: true}
+	for _, e := range entries {
+		switch e.Type {
+		case "cluster":
+			status.Standalone = false
+			status.Name = e.Name
+			status.Version = e.Version
+			status.Quorate = bool(e.Quorate)
+			status.NodeCount = e.Nodes
+		case "node":
+			status.Nodes = append(status.Nodes, ClusterNodeStatus{
+				Name:   e.Name,
+				NodeID: e.NodeID,
+				IP:     e.IP,
+				Online: bool(e.Online),
+				Local:  bool(e.Local),
+				Level:  e.Level,
+			})
+		}
+	}
+	if status.Standalone {
+		status.NodeCount = len(status.Nodes)
+	}
+	sort.Slice(status.Nodes, func(i, j int) bool { return status.Nodes[i].Name < status.Nodes[j].Name })
+	return status, nil
+}
+
+// IsQuorate reports whether the cluster currently has quorum. A standalone
+// node is always quorate.
+func (c *Client) IsQuorate(ctx context.Context) (bool, error) {
+	status, err := c.GetClusterStatus(ctx)
+	if err != nil {
+		return false, err
+	}
+	return status.Quorate, nil
+}
+
+// ClusterConfigNode is a node entry of the corosync configuration.
+type ClusterConfigNode struct {
+	Name        string         `json:"name"`
+	NodeID      int            `json:"nodeid"`
+	QuorumVotes int            `json:"quorumVotes"`
+	Links       map[int]string `json:"links"` // corosync link number to address
+	// Raw holds additional fields not mapped above.
+	Raw map[string]string `json:"raw,omitempty"`
+}
+
+// ParseClusterConfigNode maps a raw /cluster/config/nodes entry onto a ClusterConfigNode.
+func ParseClusterConfigNode(raw map[string]any) *ClusterConfigNode {
+	n := &ClusterConfigNode{Links: make(map[int]string), Raw: make(map[string]string)}
+	for k, v := range raw {
+		switch k {
+		case "name", "node":
+			n.Name = fmt.Sprintf("%v", v)
+		case "nodeid":
+			n.NodeID = toInt(v)
+		case "quorum_votes":
+			n.QuorumVotes = toInt(v)
+		default:
+			if link, ok := parseIndexedKey(corosyncLinkKeyRegex, k); ok {
+				n.Links[link] = fmt.Sprintf("%v", v)
+				continue
+			}
+			n.Raw[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return n
+}
+
+// ListClusterConfigNodes returns the nodes of the corosync configuration.
+func (c *Client) ListClusterConfigNodes(ctx context.Context) ([]ClusterConfigNode, error) {
+	var raw []map[string]any
+	if err := c.do(ctx, http.MethodGet, apiClusterPath+"/config/nodes", nil, nil, false, &raw); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]ClusterConfigNode, 0, len(raw))
+	for _, r := range raw {
+		nodes = append(nodes, *ParseClusterConfigNode(r))
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].NodeID < nodes[j].NodeID })
+	return nodes, nil
+}
+
+// CorosyncTotem is the totem section of the corosync configuration.
+type CorosyncTotem struct {
+	ClusterName   string                    `json:"cluster_name"`
+	ConfigVersion json.Number               `json:"config_version"`
+	Version       json.Number               `json:"version"`
+	IPVersion     string                    `json:"ip_version,omitempty"`
+	LinkMode      string                    `json:"link_mode,omitempty"`
+	SecAuth       string                    `json:"secauth,omitempty"`
+	Interface     map[string]map[string]any `json:"interface,omitempty"` // keyed by link number
+}
+
+// GetCorosyncTotem returns the corosync totem settings.
+func (c *Client) GetCorosyncTotem(ctx context.Context) (*CorosyncTotem, error) {
+	var totem CorosyncTotem
+	if err := c.do(ctx, http.MethodGet, apiClusterPath+"/config/totem", nil, nil, false, &totem); err != nil {
+		return nil, err
+	}
+	return &totem, nil
+}
+
+// ClusterJoinNode is a node entry of the cluster join information.
+type ClusterJoinNode struct {
+	Name           string      `json:"name"`
+	NodeID         json.Number `json:"nodeid"`
+	PveAddr        string      `json:"pve_addr"`
+	PveFingerprint string      `json:"pve_fp"`
+	QuorumVotes    json.Number `json:"quorum_votes"`
+	Ring0Addr      string      `json:"ring0_addr,omitempty"`
+}
+
+// ClusterJoinInfo is the decoded response of /cluster/config/join.
+type ClusterJoinInfo struct {
+	ConfigDigest  string            `json:"config_digest"`
+	PreferredNode string            `json:"preferred_node"`
+	NodeList      []ClusterJoinNode `json:"nodelist"`
+	Totem         CorosyncTotem     `json:"totem"`
+}
+
+// GetClusterJoinInfo returns the information a new node needs to join the
+// cluster, including each node's API certificate fingerprint.
+func (c *Client) GetClusterJoinInfo(ctx context.Context) (*ClusterJoinInfo, error) {
+	var info ClusterJoinInfo
+	if err := c.do(ctx, http.MethodGet, apiClusterPath+"/config/join", nil, nil, false, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
@@ -0,0 +1,136 @@
+package proxmox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ClusterResourceFilter restricts ClusterResources to one kind of resource.
+type ClusterResourceFilter string
+
+const (
+	AllResources     ClusterResourceFilter = ""
+	VmResources      ClusterResourceFilter = "vm" // qemu and lxc guests
+	StorageResources ClusterResourceFilter = "storage"
+	NodeResources    ClusterResourceFilter = "node"
+	SdnResources     ClusterResourceFilter = "sdn"
+)
+
+// ClusterPool is a resource pool entry of /cluster/resources.
+type ClusterPool struct {
+	Pool string `json:"pool"`
+}
+
+// ClusterSdnZone is an SDN zone entry of /cluster/resources.
+type ClusterSdnZone struct {
+	Sdn    string `json:"sdn"`
+	Node   string `json:"node"`
+	Status string `json:"status"`
+}
+
+// clusterStorageResource is a storage entry of /cluster/resources as PVE returns it.
+type clusterStorageResource struct {
+	Storage    string `json:"storage"`
+	Node       string `json:"node"`
+	Status     string `json:"status"` // "available", "unknown" or "disabled"
+	PluginType string `json:"plugintype"`
+	Content    string `json:"content"`
+	Shared     int    `json:"shared"`
+	Disk       int64  `json:"disk"`
+	MaxDisk    int64  `json:"maxdisk"`
+}
+
+func (s *clusterStorageResource) toPool() *ProxmoxStoragePool {
+	pool := &ProxmoxStoragePool{
+		Name:         s.Storage,
+		Type:         ParseProxmoxStorageType(s.PluginType),
+		Capabilities: ParseProxmoxStorageContent(s.Content),
+		Shared:       s.Shared == 1,
+		Node:         s.Node,
+		TotalBytes:   s.MaxDisk,
+		Used:         s.Disk,
+		Avail:        s.MaxDisk - s.Disk,
+		Active:       s.Status == "available",
+		Enabled:      s.Status != "disabled",
+	}
+	if pool.TotalBytes > 0 {
+		pool.UsedFraction = float64(pool.Used) / float64(pool.TotalBytes)
+	}
+	return pool
+}
+
+// ClusterResource is one entry of /cluster/resources. Type selects which of the
+// typed fields is set; entries of a type this package does not know keep only
+// Raw.
+type ClusterResource struct {
+	ID   string `json:"id"`   // e.g. "qemu/100", "node/pve1", "storage/pve1/local"
+	Type string `json:"type"` // "qemu", "lxc", "node", "storage", "pool" or "sdn"
+
+	Qemu    *QemuVm             `json:"qemu,omitempty"`
+	Lxc     *LxcStatus          `json:"lxc,omitempty"`
+	Node    *NodeSummary        `json:"node,omitempty"`
+	Storage *ProxmoxStoragePool `json:"storage,omitempty"`
+	Pool    *ClusterPool        `json:"pool,omitempty"`
+	Sdn     *ClusterSdnZone     `json:"sdn,omitempty"`
+
+	// Raw is the undecoded entry.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes an entry into the struct matching its type.
+func (r *ClusterResource) UnmarshalJSON(data []byte) error {
+	var head struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return err
+	}
+	*r = ClusterResource{ID: head.ID, Type: head.Type, Raw: append(json.RawMessage(nil), data...)}
+
+	var err error
+	switch head.Type {
+	case "qemu":
+		r.Qemu = &QemuVm{}
+		err = json.Unmarshal(data, r.Qemu)
+	case "lxc":
+		r.Lxc = &LxcStatus{}
+		err = json.Unmarshal(data, r.Lxc)
+	case "node":
+		r.Node = &NodeSummary{}
+		err = json.Unmarshal(data, r.Node)
+	case "storage":
+		var s clusterStorageResource
+		if err = json.Unmarshal(data, &s); err == nil {
+			r.Storage = s.toPool()
+		}
+	case "pool":
+		r.Pool = &ClusterPool{}
+		err = json.Unmarshal(data, r.Pool)
+	case "sdn":
+		r.Sdn = &ClusterSdnZone{}
+		err = json.Unmarshal(data, r.Sdn)
+	}
+	if err != nil {
+		return fmt.Errorf("decoding %s resource %s: %w", head.Type, head.ID, err)
+	}
+	return nil
+}
+
+// ClusterResources returns every resource of the cluster in one request,
+// optionally filtered to a single kind.
+func (c *Client) ClusterResources(ctx context.Context, filter ClusterResourceFilter) ([]ClusterResource, error) {
+	path := apiClusterResourcesPath
+	if filter != AllResources {
+		path += "?type=" + url.QueryEscape(string(filter))
+	}
+
+	var resources []ClusterResource
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
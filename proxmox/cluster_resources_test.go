@@ -0,0 +1,12 @@
+package proxmox
+
+import "testing"
+
+func TestClusterStorageResourceEnabled(t *testing.T) {
+	for status, want := range map[string]bool{"available": true, "unknown": true, "disabled": false} {
+		pool := (&clusterStorageResource{Storage: "local", Status: status}).toPool()
+		if pool.Enabled != want {
+			t.Errorf("status %q: Enabled = %v, want %v", status, pool.Enabled, want)
+		}
+	}
+}
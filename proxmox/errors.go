@@ -0,0 +1,45 @@
+package proxmox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a single invalid field of a request.
+type FieldError struct {
+	Field string
+	Msg   string
+}
+
+func (e FieldError) String() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Msg)
+}
+
+// ValidationError collects every invalid field found while validating a request,
+// so callers can report all problems at once.
+type ValidationError struct {
+	Resource string
+	Fields   []FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	problems := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		problems = append(problems, f.String())
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Resource, strings.Join(problems, "; "))
+}
+
+// Add records a problem with field.
+func (e *ValidationError) Add(field, format string, a ...any) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Msg: fmt.Sprintf(format, a...)})
+}
+
+// Err returns e if any problems were recorded, otherwise nil.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,78 @@
+package proxmox
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLxcContainerValidateReportsAllFields(t *testing.T) {
+	lxc := &LxcContainer{
+		VmId:          -1,
+		SshPublicKeys: []string{"ssh-ed25519 AAAA", " "},
+	}
+
+	_, err := lxc.ToFormParams()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("ToFormParams error = %v, want *ValidationError", err)
+	}
+	if verr.Resource != "LxcContainer" {
+		t.Errorf("Resource = %q, want LxcContainer", verr.Resource)
+	}
+
+	var fields []string
+	for _, f := range verr.Fields {
+		fields = append(fields, f.Field)
+	}
+	want := []string{"vmid", "hostname", "ostemplate", "storage", "ssh_public_keys"}
+	if !slices.Equal(fields, want) {
+		t.Errorf("invalid fields = %v, want %v", fields, want)
+	}
+}
+
+func TestValidationErrorErrWithoutFields(t *testing.T) {
+	verr := &ValidationError{Resource: "LxcContainer"}
+	if err := verr.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestLxcContainerValidateAcceptsValid(t *testing.T) {
+	lxc := &LxcContainer{VmId: 101, Hostname: "ct101", OsTemplate: "local:vztmpl/debian-12.tar.zst", Storage: "local-lvm", Password: "secret"}
+	if _, err := lxc.ToFormParams(); err != nil {
+		t.Errorf("ToFormParams: %v", err)
+	}
+}
+
+func TestLxcContainerRootFsSize(t *testing.T) {
+	tests := []struct {
+		size, want string
+		wantErr    bool
+	}{
+		{size: "8G", want: "local-lvm:8"},
+		{size: "512M", want: "local-lvm:0.5"},
+		{size: "1T", want: "local-lvm:1024"},
+		{size: "16", want: "local-lvm:16"},
+		{size: "8X", wantErr: true},
+		{size: "0G", wantErr: true},
+	}
+	for _, tt := range tests {
+		lxc := &LxcContainer{VmId: 101, Hostname: "ct101", OsTemplate: "local:vztmpl/debian-12.tar.zst", Storage: "local-lvm", Password: "secret", RootFsSize: tt.size}
+		params, err := lxc.ToFormParams()
+		if tt.wantErr {
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Fields[0].Field != "rootfs" {
+				t.Errorf("RootFsSize %q: error = %v, want a rootfs ValidationError", tt.size, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("RootFsSize %q: %v", tt.size, err)
+			continue
+		}
+		if got := params["rootfs"]; got != tt.want {
+			t.Errorf("RootFsSize %q: rootfs = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package proxmox
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxLxcNetInterfaces = 32
+	maxLxcMountPoints   = 256
+)
+
+var (
+	lxcNetKeyRegex = regexp.MustCompile(`^net(\d+)$`)
+	lxcMpKeyRegex  = regexp.MustCompile(`^mp(\d+)$`)
+)
+
+// LxcNetInterface is a typed netN entry of a container config, e.g.
+// "name=eth0,bridge=vmbr0,hwaddr=BC:24:11:00:00:01,ip=dhcp,type=veth".
+type LxcNetInterface struct {
+	ID       int     `json:"id"`                 // N in netN
+	Name     string  `json:"name"`               // Interface name inside the container, e.g. "eth0"
+	Bridge   string  `json:"bridge,omitempty"`   // Host bridge, e.g. "vmbr0"
+	HwAddr   string  `json:"hwaddr,omitempty"`   // MAC address
+	IP       string  `json:"ip,omitempty"`       // CIDR, "dhcp" or "manual"
+	Gateway  string  `json:"gw,omitempty"`       // IPv4 gateway
+	IP6      string  `json:"ip6,omitempty"`      // CIDR, "auto", "dhcp" or "manual"
+	Gateway6 string  `json:"gw6,omitempty"`      // IPv6 gateway
+	Tag      int     `json:"tag,omitempty"`      // VLAN tag
+	Trunks   string  `json:"trunks,omitempty"`   // VLAN IDs separated by ';'
+	Firewall *bool   `json:"firewall,omitempty"` // Enable the PVE firewall on this interface
+	LinkDown *bool   `json:"link_down,omitempty"`
+	MTU      int     `json:"mtu,omitempty"`
+	Rate     float64 `json:"rate,omitempty"` // Rate limit in MB/s
+	Type     string  `json:"type,omitempty"` // Always "veth" today
+	// Options holds properties not mapped above so they survive a round trip.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// ParseLxcNetInterface parses the value of config key netN.
+func ParseLxcNetInterface(id int, s string) (*LxcNetInterface, error) {
+	props, err := parsePropertyString(s, "")
+	if err != nil {
+		return nil, fmt.Errorf("parsing net%d: %w", id, err)
+	}
+
+	n := &LxcNetInterface{
+		ID:       id,
+		Name:     props.take("name"),
+		Bridge:   props.take("bridge"),
+		HwAddr:   props.take("hwaddr"),
+		IP:       props.take("ip"),
+		Gateway:  props.take("gw"),
+		IP6:      props.take("ip6"),
+		Gateway6: props.take("gw6"),
+		Trunks:   props.take("trunks"),
+		Type:     props.take("type"),
+	}
+	if n.Tag, err = props.takeInt("tag"); err != nil {
+		return nil, fmt.Errorf("parsing net%d: %w", id, err)
+	}
+	if n.MTU, err = props.takeInt("mtu"); err != nil {
+		return nil, fmt.Errorf("parsing net%d: %w", id, err)
+	}
+	if n.Rate, err = props.takeFloat("rate"); err != nil {
+		return nil, fmt.Errorf("parsing net%d: %w", id, err)
+	}
+	if n.Firewall, err = props.takeBool("firewall"); err != nil {
+		return nil, fmt.Errorf("parsing net%d: %w", id, err)
+	}
+	if n.LinkDown, err = props.takeBool("link_down"); err != nil {
+		return nil, fmt.Errorf("parsing net%d: %w", id, err)
+	}
+	n.Options = props.remaining()
+	return n, nil
+}
+
+// Key returns the config key for the interface, e.g. "net0".
+func (n *LxcNetInterface) Key() string {
+	return fmt.Sprintf("net%d", n.ID)
+}
+
+// String formats the interface as a PVE property string.
+func (n *LxcNetInterface) String() string {
+	var b propertyStringBuilder
+	b.str("name", n.Name)
+	b.str("bridge", n.Bridge)
+	b.bool("firewall", n.Firewall)
+	b.str("gw", n.Gateway)
+	b.str("gw6", n.Gateway6)
+	b.str("hwaddr", n.HwAddr)
+	b.str("ip", n.IP)
+	b.str("ip6", n.IP6)
+	b.bool("link_down", n.LinkDown)
+	b.int("mtu", n.MTU)
+	b.float("rate", n.Rate)
+	b.int("tag", n.Tag)
+	b.str("trunks", n.Trunks)
+	b.str("type", n.Type)
+	b.extra(n.Options)
+	return b.String()
+}
+
+// LxcMountPoint is a typed mpN entry of a container config, e.g.
+// "local-lvm:vm-100-disk-1,mp=/srv/data,backup=1,size=32G".
+type LxcMountPoint struct {
+	ID           int    `json:"id"`                     // N in mpN
+	Volume       string `json:"volume"`                 // Volume ID, host path, or "storage:size" on create
+	MountPath    string `json:"mp"`                     // Path inside the container
+	Size         string `json:"size,omitempty"`         // e.g. "32G"
+	ACL          *bool  `json:"acl,omitempty"`          // Explicitly enable or disable ACL support
+	Backup       *bool  `json:"backup,omitempty"`       // Include in vzdump backups
+	Quota        *bool  `json:"quota,omitempty"`        // Enable user quotas
+	ReadOnly     *bool  `json:"ro,omitempty"`           // Read-only mount
+	Shared       *bool  `json:"shared,omitempty"`       // Volume is available on all nodes
+	Replicate    *bool  `json:"replicate,omitempty"`    // Include in storage replication
+	MountOptions string `json:"mountoptions,omitempty"` // Extra mount options separated by ';'
+	// Options holds properties not mapped above so they survive a round trip.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// ParseLxcMountPoint parses the value of config key mpN.
+func ParseLxcMountPoint(id int, s string) (*LxcMountPoint, error) {
+	props, err := parsePropertyString(s, "volume")
+	if err != nil {
+		return nil, fmt.Errorf("parsing mp%d: %w", id, err)
+	}
+
+	mp := &LxcMountPoint{
+		ID:           id,
+		Volume:       props.take("volume"),
+		MountPath:    props.take("mp"),
+		Size:         props.take("size"),
+		MountOptions: props.take("mountoptions"),
+	}
+	for key, dst := range map[string]**bool{
+		"acl":       &mp.ACL,
+		"backup":    &mp.Backup,
+		"quota":     &mp.Quota,
+		"ro":        &mp.ReadOnly,
+		"shared":    &mp.Shared,
+		"replicate": &mp.Replicate,
+	} {
+		if *dst, err = props.takeBool(key); err != nil {
+			return nil, fmt.Errorf("parsing mp%d: %w", id, err)
+		}
+	}
+	mp.Options = props.remaining()
+	return mp, nil
+}
+
+// Key returns the config key for the mount point, e.g. "mp0".
+func (mp *LxcMountPoint) Key() string {
+	return fmt.Sprintf("mp%d", mp.ID)
+}
+
+// String formats the mount point as a PVE property string.
+func (mp *LxcMountPoint) String() string {
+	var b propertyStringBuilder
+	b.value(mp.Volume)
+	b.str("mp", mp.MountPath)
+	b.bool("acl", mp.ACL)
+	b.bool("backup", mp.Backup)
+	b.str("mountoptions", mp.MountOptions)
+	b.bool("quota", mp.Quota)
+	b.bool("replicate", mp.Replicate)
+	b.bool("ro", mp.ReadOnly)
+	b.bool("shared", mp.Shared)
+	b.str("size", mp.Size)
+	b.extra(mp.Options)
+	return b.String()
+}
+
+// parseIndexedKey returns N if key matches re (e.g. net(\d+)).
+func parseIndexedKey(re *regexp.Regexp, key string) (int, bool) {
+	m := re.FindStringSubmatch(key)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+func sortLxcNetInterfaces(nets []LxcNetInterface) {
+	sort.Slice(nets, func(i, j int) bool { return nets[i].ID < nets[j].ID })
+}
+
+func sortLxcMountPoints(mps []LxcMountPoint) {
+	sort.Slice(mps, func(i, j int) bool { return mps[i].ID < mps[j].ID })
+}
+
+// validateNetworksAndMounts records duplicate or out of range netN/mpN IDs and
+// mount points missing a volume or path.
+func (lxc *LxcContainer) validateNetworksAndMounts(verr *ValidationError) {
+	seenNet := make(map[int]bool)
+	for _, n := range lxc.Networks {
+		switch {
+		case n.ID < 0 || n.ID >= maxLxcNetInterfaces:
+			verr.Add(n.Key(), "id must be between 0 and %d", maxLxcNetInterfaces-1)
+		case seenNet[n.ID]:
+			verr.Add(n.Key(), "duplicate interface id")
+		}
+		seenNet[n.ID] = true
+		if n.Name == "" {
+			verr.Add(n.Key(), "name required")
+		}
+	}
+
+	seenMp := make(map[int]bool)
+	for _, mp := range lxc.MountPoints {
+		switch {
+		case mp.ID < 0 || mp.ID >= maxLxcMountPoints:
+			verr.Add(mp.Key(), "id must be between 0 and %d", maxLxcMountPoints-1)
+		case seenMp[mp.ID]:
+			verr.Add(mp.Key(), "duplicate mount point id")
+		}
+		seenMp[mp.ID] = true
+		if mp.Volume == "" {
+			verr.Add(mp.Key(), "volume required")
+		}
+		if mp.MountPath == "" {
+			verr.Add(mp.Key(), "mp path required")
+		}
+	}
+}
+
+// networksWithBridge returns Networks with Bridge applied. PVE has no standalone
+// bridge parameter, so Bridge is set on the first interface when it has none, or
+// an eth0 interface is added if there are no interfaces.
+func (lxc *LxcContainer) networksWithBridge() []LxcNetInterface {
+	nets := append([]LxcNetInterface(nil), lxc.Networks...)
+	if lxc.Bridge == "" {
+		return nets
+	}
+	if len(nets) == 0 {
+		return []LxcNetInterface{{ID: 0, Name: "eth0", Bridge: lxc.Bridge}}
+	}
+	if nets[0].Bridge == "" {
+		nets[0].Bridge = lxc.Bridge
+	}
+	return nets
+}
+
+// mountTypeRegex matches a file system type as the features schema allows it.
+var mountTypeRegex = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// LxcFeatures is the typed features entry of a container config, e.g.
+// "keyctl=1,nesting=1".
+type LxcFeatures struct {
+	Nesting    bool     `json:"nesting,omitempty"`      // Allow nested containers (required for Docker)
+	Keyctl     bool     `json:"keyctl,omitempty"`       // Allow keyctl(); unprivileged containers only
+	Fuse       bool     `json:"fuse,omitempty"`         // Allow FUSE mounts
+	Mknod      bool     `json:"mknod,omitempty"`        // Allow mknod(); unprivileged containers only
+	ForceRwSys bool     `json:"force_rw_sys,omitempty"` // Mount /sys rw; unprivileged containers only
+	Mount      []string `json:"mount,omitempty"`        // File system types allowed to be mounted
+}
+
+// DockerLxcFeatures returns the features an unprivileged container needs to run Docker.
+func DockerLxcFeatures() *LxcFeatures {
+	return &LxcFeatures{Nesting: true, Keyctl: true}
+}
+
+// ParseLxcFeatures parses the value of the features config key.
+func ParseLxcFeatures(s string) (*LxcFeatures, error) {
+	props, err := parsePropertyString(s, "")
+	if err != nil {
+		return nil, fmt.Errorf("parsing features: %w", err)
+	}
+
+	f := &LxcFeatures{}
+	for key, dst := range map[string]*bool{
+		"nesting":      &f.Nesting,
+		"keyctl":       &f.Keyctl,
+		"fuse":         &f.Fuse,
+		"mknod":        &f.Mknod,
+		"force_rw_sys": &f.ForceRwSys,
+	} {
+		v, err := props.takeBool(key)
+		if err != nil {
+			return nil, fmt.Errorf("parsing features: %w", err)
+		}
+		*dst = v != nil && *v
+	}
+	if mount := props.take("mount"); mount != "" {
+		f.Mount = strings.Split(mount, ";")
+	}
+	if len(props) > 0 {
+		return nil, fmt.Errorf("parsing features: unknown feature(s) %v", props.remaining())
+	}
+	return f, nil
+}
+
+// String formats the features as a PVE property string.
+func (f *LxcFeatures) String() string {
+	var b propertyStringBuilder
+	for _, flag := range []struct {
+		key string
+		on  bool
+	}{
+		{"force_rw_sys", f.ForceRwSys},
+		{"fuse", f.Fuse},
+		{"keyctl", f.Keyctl},
+		{"mknod", f.Mknod},
+	} {
+		if flag.on {
+			b.str(flag.key, "1")
+		}
+	}
+	b.str("mount", strings.Join(f.Mount, ";"))
+	if f.Nesting {
+		b.str("nesting", "1")
+	}
+	return b.String()
+}
+
+// validate records feature combinations PVE refuses for the container's
+// privilege level. Mount types are only checked against the features schema:
+// whether a container may actually mount them is decided by PVE at start time.
+func (f *LxcFeatures) validate(verr *ValidationError, unprivileged bool) {
+	for _, fs := range f.Mount {
+		if !mountTypeRegex.MatchString(fs) {
+			verr.Add("features", "invalid mount type %q", fs)
+		}
+	}
+	if unprivileged {
+		return
+	}
+
+	if f.Keyctl {
+		verr.Add("features", "keyctl is only supported for unprivileged containers")
+	}
+	if f.Mknod {
+		verr.Add("features", "mknod is only supported for unprivileged containers")
+	}
+	if f.ForceRwSys {
+		verr.Add("features", "force_rw_sys is only supported for unprivileged containers")
+	}
+}
@@ -0,0 +1,129 @@
+package proxmox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLxcNetInterfaceRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string // canonical form
+	}{
+		{
+			name: "canonical",
+			in:   "name=eth0,bridge=vmbr0,firewall=1,hwaddr=BC:24:11:00:00:01,ip=dhcp,type=veth",
+			want: "name=eth0,bridge=vmbr0,firewall=1,hwaddr=BC:24:11:00:00:01,ip=dhcp,type=veth",
+		},
+		{
+			name: "reordered",
+			in:   "type=veth,tag=20,mtu=9000,ip=10.0.0.5/24,gw=10.0.0.1,bridge=vmbr1,name=eth1",
+			want: "name=eth1,bridge=vmbr1,gw=10.0.0.1,ip=10.0.0.5/24,mtu=9000,tag=20,type=veth",
+		},
+		{
+			name: "trunks",
+			in:   "name=eth0,bridge=vmbr0,trunks=10;20,link_down=0,rate=12.5",
+			want: "name=eth0,bridge=vmbr0,link_down=0,rate=12.5,trunks=10;20",
+		},
+		{
+			name: "unknown keys",
+			in:   "name=eth0,zz=last,bridge=vmbr0,aa=first",
+			want: "name=eth0,bridge=vmbr0,aa=first,zz=last",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := ParseLxcNetInterface(0, tt.in)
+			if err != nil {
+				t.Fatalf("ParseLxcNetInterface: %v", err)
+			}
+			if got := n.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			again, err := ParseLxcNetInterface(0, n.String())
+			if err != nil {
+				t.Fatalf("re-parse: %v", err)
+			}
+			if !reflect.DeepEqual(again, n) {
+				t.Errorf("round trip = %+v, want %+v", again, n)
+			}
+		})
+	}
+}
+
+func TestLxcNetInterfaceTrunks(t *testing.T) {
+	n, err := ParseLxcNetInterface(1, "name=eth0,trunks=10;20")
+	if err != nil {
+		t.Fatalf("ParseLxcNetInterface: %v", err)
+	}
+	if n.Trunks != "10;20" || n.Options != nil {
+		t.Errorf("Trunks = %q, Options = %v", n.Trunks, n.Options)
+	}
+}
+
+func TestLxcMountPointRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "volume",
+			in:   "local-lvm:vm-100-disk-1,mp=/srv/data,backup=1,size=32G",
+			want: "local-lvm:vm-100-disk-1,mp=/srv/data,backup=1,size=32G",
+		},
+		{
+			name: "reordered",
+			in:   "size=8G,ro=1,mp=/mnt/ro,volume=tank:subvol-100-disk-2,acl=0",
+			want: "tank:subvol-100-disk-2,mp=/mnt/ro,acl=0,ro=1,size=8G",
+		},
+		{
+			name: "bind mount",
+			in:   "/srv/shared,mp=/shared,mountoptions=noatime;nosuid,shared=1,replicate=0",
+			want: "/srv/shared,mp=/shared,mountoptions=noatime;nosuid,replicate=0,shared=1",
+		},
+		{
+			name: "unknown keys",
+			in:   "local:100/vm-100-disk-0.raw,mp=/data,zz=1,aa=2",
+			want: "local:100/vm-100-disk-0.raw,mp=/data,aa=2,zz=1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mp, err := ParseLxcMountPoint(0, tt.in)
+			if err != nil {
+				t.Fatalf("ParseLxcMountPoint: %v", err)
+			}
+			if got := mp.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			again, err := ParseLxcMountPoint(0, mp.String())
+			if err != nil {
+				t.Fatalf("re-parse: %v", err)
+			}
+			if !reflect.DeepEqual(again, mp) {
+				t.Errorf("round trip = %+v, want %+v", again, mp)
+			}
+		})
+	}
+}
+
+func TestLxcFeaturesValidateMountTypes(t *testing.T) {
+	tests := []struct {
+		mount   []string
+		wantErr bool
+	}{
+		{mount: []string{"nfs", "cifs"}},
+		{mount: []string{"ext4", "xfs", "btrfs", "zfs", "smb3"}},
+		{mount: []string{"nfs4;cifs"}, wantErr: true},
+		{mount: []string{"fuse.sshfs"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		verr := &ValidationError{Resource: "LxcContainer"}
+		(&LxcFeatures{Nesting: true, Mount: tt.mount}).validate(verr, true)
+		if err := verr.Err(); (err != nil) != tt.wantErr {
+			t.Errorf("mount=%v: error = %v, wantErr %v", tt.mount, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CreateLxcSnapshot snapshots a container.
+func (c *Client) CreateLxcSnapshot(ctx context.Context, node string, vmid int, opts SnapshotOptions) (*Task, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, lxcPath(node, vmid, "/snapshot"), opts.toParams(false), &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// ListLxcSnapshots returns the snapshots of a container, including the "current" entry.
+func (c *Client) ListLxcSnapshots(ctx context.Context, node string, vmid int) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	if err := c.do(ctx, http.MethodGet, lxcPath(node, vmid, "/snapshot"), nil, nil, false, &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// RollbackLxcSnapshot rolls a container back to snapshot name, optionally
+// starting it once the rollback completes.
+func (c *Client) RollbackLxcSnapshot(ctx context.Context, node string, vmid int, name string, start bool) (*Task, error) {
+	verr := &ValidationError{Resource: "Snapshot"}
+	validateSnapshotName(verr, "snapname", name)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if start {
+		params.Set("start", "1")
+	}
+
+	path := lxcPath(node, vmid, fmt.Sprintf("/snapshot/%s/rollback", url.PathEscape(name)))
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, path, params, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// DeleteLxcSnapshot deletes snapshot name. force removes the snapshot from the
+// config even if deleting the underlying storage snapshot fails.
+func (c *Client) DeleteLxcSnapshot(ctx context.Context, node string, vmid int, name string, force bool) (*Task, error) {
+	verr := &ValidationError{Resource: "Snapshot"}
+	validateSnapshotName(verr, "snapname", name)
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	path := lxcPath(node, vmid, "/snapshot/"+url.PathEscape(name))
+	if force {
+		path += "?force=1"
+	}
+
+	var upid string
+	if err := c.do(ctx, http.MethodDelete, path, nil, nil, true, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// CloneLxc clones a container or container template.
+func (c *Client) CloneLxc(ctx context.Context, node string, vmid int, opts CloneOptions) (*Task, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, lxcPath(node, vmid, "/clone"), opts.toParams("hostname"), &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// ConvertLxcToTemplate converts a stopped container into a template. PVE
+// versions that do the conversion without a worker return no UPID, in which
+// case the returned task is nil.
+func (c *Client) ConvertLxcToTemplate(ctx context.Context, node string, vmid int) (*Task, error) {
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, lxcPath(node, vmid, "/template"), url.Values{}, &upid); err != nil {
+		return nil, err
+	}
+	if upid == "" {
+		return nil, nil
+	}
+	return c.newTask(node, upid), nil
+}
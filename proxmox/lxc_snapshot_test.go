@@ -0,0 +1,137 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+const testUPID = "UPID:pve1:00000001:00000001:00000001:vzsnapshot:101:root@pam:"
+
+// recordedRequest is the last request a recordingClient received.
+type recordedRequest struct {
+	method, path string
+	form         url.Values
+}
+
+// recordingClient returns a client that records its requests in the returned
+// recordedRequest and answers them with data as the response "data" member.
+func recordingClient(t *testing.T, data string) (*Client, *recordedRequest) {
+	rec := &recordedRequest{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		rec.method, rec.path, rec.form = r.Method, r.URL.RequestURI(), r.PostForm
+		fmt.Fprintf(w, `{"data":%s}`, data)
+	})
+	return c, rec
+}
+
+func TestLxcSnapshotCalls(t *testing.T) {
+	ctx := context.Background()
+	c, rec := recordingClient(t, `"`+testUPID+`"`)
+
+	task, err := c.CreateLxcSnapshot(ctx, "pve1", 101, SnapshotOptions{Name: "pre-upgrade", Description: "before apt", VmState: true})
+	if err != nil {
+		t.Fatalf("CreateLxcSnapshot: %v", err)
+	}
+	if task.UPID != testUPID {
+		t.Errorf("UPID = %q", task.UPID)
+	}
+	if rec.method != http.MethodPost || rec.path != "/api2/json/nodes/pve1/lxc/101/snapshot" {
+		t.Errorf("create sent %s %s", rec.method, rec.path)
+	}
+	if rec.form.Get("snapname") != "pre-upgrade" || rec.form.Get("description") != "before apt" || rec.form.Has("vmstate") {
+		t.Errorf("create form = %v", rec.form)
+	}
+
+	if _, err := c.RollbackLxcSnapshot(ctx, "pve1", 101, "pre-upgrade", true); err != nil {
+		t.Fatalf("RollbackLxcSnapshot: %v", err)
+	}
+	if rec.method != http.MethodPost || rec.path != "/api2/json/nodes/pve1/lxc/101/snapshot/pre-upgrade/rollback" || rec.form.Get("start") != "1" {
+		t.Errorf("rollback sent %s %s %v", rec.method, rec.path, rec.form)
+	}
+
+	if _, err := c.DeleteLxcSnapshot(ctx, "pve1", 101, "pre-upgrade", true); err != nil {
+		t.Fatalf("DeleteLxcSnapshot: %v", err)
+	}
+	if rec.method != http.MethodDelete || rec.path != "/api2/json/nodes/pve1/lxc/101/snapshot/pre-upgrade?force=1" {
+		t.Errorf("delete sent %s %s", rec.method, rec.path)
+	}
+}
+
+func TestLxcSnapshotRejectsInvalidNames(t *testing.T) {
+	ctx := context.Background()
+	c, rec := recordingClient(t, `"`+testUPID+`"`)
+
+	if _, err := c.CreateLxcSnapshot(ctx, "pve1", 101, SnapshotOptions{Name: "before upgrade"}); err == nil {
+		t.Error("CreateLxcSnapshot accepted an invalid name")
+	}
+	if _, err := c.RollbackLxcSnapshot(ctx, "pve1", 101, "../current", false); err == nil {
+		t.Error("RollbackLxcSnapshot accepted an invalid name")
+	}
+	if _, err := c.DeleteLxcSnapshot(ctx, "pve1", 101, "", false); err == nil {
+		t.Error("DeleteLxcSnapshot accepted an empty name")
+	}
+	if rec.method != "" {
+		t.Errorf("request sent for an invalid name: %s %s", rec.method, rec.path)
+	}
+}
+
+func TestListLxcSnapshots(t *testing.T) {
+	c, _ := recordingClient(t, `[{"name":"pre-upgrade","snaptime":1700000000},{"name":"current","parent":"pre-upgrade"}]`)
+
+	snapshots, err := c.ListLxcSnapshots(context.Background(), "pve1", 101)
+	if err != nil {
+		t.Fatalf("ListLxcSnapshots: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].IsCurrent() || !snapshots[1].IsCurrent() || snapshots[1].Parent != "pre-upgrade" {
+		t.Errorf("snapshots = %+v", snapshots)
+	}
+}
+
+func TestCloneLxc(t *testing.T) {
+	c, rec := recordingClient(t, `"`+testUPID+`"`)
+
+	opts := CloneOptions{NewID: 200, Name: "ct200", Full: true, Storage: "local-lvm", SnapName: "pre-upgrade"}
+	if _, err := c.CloneLxc(context.Background(), "pve1", 101, opts); err != nil {
+		t.Fatalf("CloneLxc: %v", err)
+	}
+	if rec.method != http.MethodPost || rec.path != "/api2/json/nodes/pve1/lxc/101/clone" {
+		t.Errorf("clone sent %s %s", rec.method, rec.path)
+	}
+	want := url.Values{"newid": {"200"}, "hostname": {"ct200"}, "full": {"1"}, "storage": {"local-lvm"}, "snapname": {"pre-upgrade"}}
+	for k, v := range want {
+		if rec.form.Get(k) != v[0] {
+			t.Errorf("clone %s = %q, want %q", k, rec.form.Get(k), v[0])
+		}
+	}
+	if len(rec.form) != len(want) {
+		t.Errorf("clone form = %v, want %v", rec.form, want)
+	}
+
+	if _, err := c.CloneLxc(context.Background(), "pve1", 101, CloneOptions{NewID: 200, Storage: "local-lvm"}); err == nil {
+		t.Error("CloneLxc accepted a target storage for a linked clone")
+	}
+}
+
+func TestConvertLxcToTemplate(t *testing.T) {
+	c, rec := recordingClient(t, `"`+testUPID+`"`)
+	task, err := c.ConvertLxcToTemplate(context.Background(), "pve1", 101)
+	if err != nil {
+		t.Fatalf("ConvertLxcToTemplate: %v", err)
+	}
+	if task == nil || rec.method != http.MethodPost || rec.path != "/api2/json/nodes/pve1/lxc/101/template" {
+		t.Errorf("template sent %s %s, task %v", rec.method, rec.path, task)
+	}
+
+	// Older PVE versions convert synchronously and return no UPID.
+	c, _ = recordingClient(t, `null`)
+	task, err = c.ConvertLxcToTemplate(context.Background(), "pve1", 101)
+	if err != nil || task != nil {
+		t.Errorf("ConvertLxcToTemplate = %v, %v, want nil task", task, err)
+	}
+}
@@ -0,0 +1,178 @@
+package proxmox
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// AplInfo is an entry of the appliance template catalog returned by /nodes/{node}/aplinfo.
+type AplInfo struct {
+	Template     string `json:"template"` // File name, e.g. "ubuntu-24.04-standard_24.04-2_amd64.tar.zst"
+	Type         string `json:"type"`     // "lxc" or "openvz"
+	Package      string `json:"package"`
+	Version      string `json:"version"`
+	Os           string `json:"os"`
+	Section      string `json:"section"` // e.g. "system", "turnkeylinux"
+	Headline     string `json:"headline"`
+	Description  string `json:"description,omitempty"`
+	Architecture string `json:"architecture,omitempty"`
+	Location     string `json:"location,omitempty"`
+	Source       string `json:"source,omitempty"`
+	InfoPage     string `json:"infopage,omitempty"`
+	ManageUrl    string `json:"manageurl,omitempty"`
+	Maintainer   string `json:"maintainer,omitempty"`
+	Md5Sum       string `json:"md5sum,omitempty"`
+	Sha512Sum    string `json:"sha512sum,omitempty"`
+}
+
+// ListAplInfo returns the appliance template catalog available to node.
+func (c *Client) ListAplInfo(ctx context.Context, node string) ([]AplInfo, error) {
+	apiPath := fmt.Sprintf("%s/%s/aplinfo", apiNodesPath, url.PathEscape(node))
+
+	var catalog []AplInfo
+	if err := c.do(ctx, http.MethodGet, apiPath, nil, nil, false, &catalog); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// DownloadAplTemplate downloads template (an AplInfo.Template file name) from
+// the catalog to storage.
+func (c *Client) DownloadAplTemplate(ctx context.Context, node, storage, template string) (*Task, error) {
+	if storage == "" || template == "" {
+		return nil, fmt.Errorf("storage and template are required")
+	}
+
+	params := url.Values{}
+	params.Set("storage", storage)
+	params.Set("template", template)
+
+	apiPath := fmt.Sprintf("%s/%s/aplinfo", apiNodesPath, url.PathEscape(node))
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, apiPath, params, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// ListLxcTemplates returns the container templates already present on storage.
+func (c *Client) ListLxcTemplates(ctx context.Context, node, storage string) ([]StorageVolume, error) {
+	return c.ListStorageContent(ctx, node, storage, StorageContentFilter{Content: LxcTemplates})
+}
+
+// ResolveLxcTemplate returns the volid of the newest template on storage whose
+// file name matches every word of query (see newestTemplateMatch), e.g.
+// "ubuntu 24.04" resolves to "local:vztmpl/ubuntu-24.04-standard_24.04-2_amd64.tar.zst".
+func (c *Client) ResolveLxcTemplate(ctx context.Context, node, storage, query string) (string, error) {
+	volumes, err := c.ListLxcTemplates(ctx, node, storage)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, len(volumes))
+	for i := range volumes {
+		names[i] = volumes[i].FileName()
+	}
+	idx := newestTemplateMatch(names, query)
+	if idx < 0 {
+		return "", fmt.Errorf("no template on %s/%s matches %q", node, storage, query)
+	}
+	return volumes[idx].Volid, nil
+}
+
+// FindAplTemplate returns the newest catalog entry whose template file name
+// matches every word of query, or nil if none match.
+func FindAplTemplate(catalog []AplInfo, query string) *AplInfo {
+	names := make([]string, len(catalog))
+	for i := range catalog {
+		names[i] = catalog[i].Template
+	}
+	idx := newestTemplateMatch(names, query)
+	if idx < 0 {
+		return nil
+	}
+	return &catalog[idx]
+}
+
+// newestTemplateMatch returns the index of the name matching every word of
+// query that sorts last in natural order, or -1 if none match. Names and words
+// are compared as whole "-" and "_" separated tokens, so "debian-12" matches
+// "debian-12-standard_12.7-1_amd64.tar.zst" but "debian-1" does not.
+func newestTemplateMatch(names []string, query string) int {
+	words := strings.Fields(query)
+	best := -1
+	for i, name := range names {
+		tokens := templateTokens(name)
+		matched := true
+		for _, w := range words {
+			if !containsTokenRun(tokens, templateTokens(w)) {
+				matched = false
+				break
+			}
+		}
+		if matched && (best < 0 || compareNatural(name, names[best]) > 0) {
+			best = i
+		}
+	}
+	return best
+}
+
+// templateTokens splits a lower-cased template name at "-" and "_", without
+// the archive extension.
+func templateTokens(s string) []string {
+	s = strings.ToLower(s)
+	if i := strings.Index(s, ".tar"); i > 0 {
+		s = s[:i]
+	}
+	return strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' })
+}
+
+// containsTokenRun reports whether run appears in tokens as consecutive tokens.
+func containsTokenRun(tokens, run []string) bool {
+	for i := 0; i+len(run) <= len(tokens); i++ {
+		if slices.Equal(tokens[i:i+len(run)], run) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareNatural compares a and b treating runs of digits as numbers, so that
+// "debian-12.10" sorts after "debian-12.9".
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		if unicode.IsDigit(rune(a[0])) && unicode.IsDigit(rune(b[0])) {
+			na, ra := splitDigits(a)
+			nb, rb := splitDigits(b)
+			na = strings.TrimLeft(na, "0")
+			nb = strings.TrimLeft(nb, "0")
+			if len(na) != len(nb) {
+				return cmp.Compare(len(na), len(nb))
+			}
+			if na != nb {
+				return strings.Compare(na, nb)
+			}
+			a, b = ra, rb
+			continue
+		}
+		if a[0] != b[0] {
+			return cmp.Compare(int(a[0]), int(b[0]))
+		}
+		a, b = a[1:], b[1:]
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+func splitDigits(s string) (digits, rest string) {
+	i := 0
+	for i < len(s) && unicode.IsDigit(rune(s[i])) {
+		i++
+	}
+	return s[:i], s[i:]
+}
@@ -0,0 +1,33 @@
+package proxmox
+
+import "testing"
+
+func TestNewestTemplateMatch(t *testing.T) {
+	names := []string{
+		"debian-11-standard_11.7-1_amd64.tar.zst",
+		"debian-12-standard_12.7-1_amd64.tar.zst",
+		"debian-13-standard_13.1-1_amd64.tar.zst",
+		"alpine-3.20-default_20240908_amd64.tar.xz",
+		"turnkey-alpinelinux_18.0-1_amd64.tar.gz",
+		"ubuntu-24.04-standard_24.04-2_amd64.tar.zst",
+	}
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{query: "debian", want: 2},
+		{query: "debian 12", want: 1},
+		{query: "debian-12", want: 1},
+		{query: "Debian 12.7", want: 1},
+		{query: "debian-1", want: -1},
+		{query: "alpine", want: 3},
+		{query: "ubuntu 24.04", want: 5},
+		{query: "ubuntu 24", want: -1},
+		{query: "fedora", want: -1},
+	}
+	for _, tt := range tests {
+		if got := newestTemplateMatch(names, tt.query); got != tt.want {
+			t.Errorf("newestTemplateMatch(%q) = %d, want %d", tt.query, got, tt.want)
+		}
+	}
+}
@@ -1,5 +1,16 @@
 package proxmox
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+const defaultPvePort = 8006
+
 type ProxmoxVmId int
 type ProxmoxVmName string
 
@@ -10,3 +21,102 @@ type ProxmoxNode struct {
 	LxcContainers []LxcContainer       `json:"lxcContainers,omitempty"`
 	Storage       []ProxmoxStoragePool `json:"storage,omitempty"`
 }
+
+// NodeSummary is an entry of the /nodes list.
+type NodeSummary struct {
+	Node           string  `json:"node"`
+	Status         string  `json:"status"` // "online", "offline" or "unknown"
+	ID             string  `json:"id,omitempty"`
+	Type           string  `json:"type,omitempty"`
+	Level          string  `json:"level,omitempty"` // Subscription level
+	SslFingerprint string  `json:"ssl_fingerprint,omitempty"`
+	CPU            float64 `json:"cpu,omitempty"`
+	MaxCPU         int     `json:"maxcpu,omitempty"`
+	Mem            int64   `json:"mem,omitempty"`
+	MaxMem         int64   `json:"maxmem,omitempty"`
+	Disk           int64   `json:"disk,omitempty"`
+	MaxDisk        int64   `json:"maxdisk,omitempty"`
+	Uptime         int64   `json:"uptime,omitempty"`
+}
+
+// IsOnline reports whether the cluster considers the node online.
+func (n *NodeSummary) IsOnline() bool {
+	return n.Status == "online"
+}
+
+// ListNodes returns the nodes of the cluster.
+func (c *Client) ListNodes(ctx context.Context) ([]NodeSummary, error) {
+	var nodes []NodeSummary
+	if err := c.do(ctx, http.MethodGet, apiNodesPath, nil, nil, false, &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// NodeInventoryError reports which sections of a node inventory failed to load.
+type NodeInventoryError struct {
+	Node       string
+	QemuErr    error
+	LxcErr     error
+	StorageErr error
+}
+
+// Error implements the error interface.
+func (e *NodeInventoryError) Error() string {
+	return fmt.Sprintf("inventory of node %s incomplete: %v", e.Node, errors.Join(e.Unwrap()...))
+}
+
+// Unwrap allows errors.Is / errors.As to access the section errors.
+func (e *NodeInventoryError) Unwrap() []error {
+	var errs []error
+	for _, section := range []struct {
+		name string
+		err  error
+	}{
+		{"qemu", e.QemuErr},
+		{"lxc", e.LxcErr},
+		{"storage", e.StorageErr},
+	} {
+		if section.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", section.name, section.err))
+		}
+	}
+	return errs
+}
+
+// GetNodeInventory concurrently loads the VMs, containers and storage of node.
+// If some sections fail, the inventory is still returned with the sections that
+// loaded, together with a *NodeInventoryError describing the failures.
+func (c *Client) GetNodeInventory(ctx context.Context, node string) (*ProxmoxNode, error) {
+	inv := &ProxmoxNode{Hostname: node, PvePort: c.pvePort()}
+	invErr := &NodeInventoryError{Node: node}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		inv.QemuVMs, invErr.QemuErr = c.ListVMs(ctx, node, false)
+	}()
+	go func() {
+		defer wg.Done()
+		inv.LxcContainers, invErr.LxcErr = c.ListLxcContainers(ctx, node)
+	}()
+	go func() {
+		defer wg.Done()
+		inv.Storage, invErr.StorageErr = c.ListNodeStorage(ctx, node, "")
+	}()
+	wg.Wait()
+
+	if invErr.QemuErr != nil || invErr.LxcErr != nil || invErr.StorageErr != nil {
+		return inv, invErr
+	}
+	return inv, nil
+}
+
+// pvePort returns the API port of the client's base URL.
+func (c *Client) pvePort() int {
+	if port, err := strconv.Atoi(c.baseURL.Port()); err == nil {
+		return port
+	}
+	return defaultPvePort
+}
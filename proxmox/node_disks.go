@@ -0,0 +1,394 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NodeDisk is a block device from /nodes/{node}/disks/list.
+type NodeDisk struct {
+	DevPath string `json:"devpath"`
+	Type    string `json:"type"` // "hdd", "ssd", "nvme", "usb" or "unknown"
+	Vendor  string `json:"vendor,omitempty"`
+	Model   string `json:"model,omitempty"`
+	Serial  string `json:"serial,omitempty"`
+	WWN     string `json:"wwn,omitempty"`
+	Size    int64  `json:"size"` // bytes
+	RPM     int    `json:"rpm,omitempty"`
+	// Health is the SMART overall health, e.g. "PASSED", "OK" or "UNKNOWN".
+	Health string `json:"health,omitempty"`
+	// Wearout is the remaining life in percent, nil if not reported.
+	Wearout *int `json:"wearout,omitempty"`
+	// UsedBy is e.g. "LVM", "ZFS" or "mounted"; empty for unused disks.
+	UsedBy   string `json:"usedBy,omitempty"`
+	GPT      bool   `json:"gpt"`
+	Mounted  bool   `json:"mounted"`
+	OsdID    int    `json:"osdId"`            // Ceph OSD ID, -1 if none
+	Parent   string `json:"parent,omitempty"` // Partitions only
+	ByIDLink string `json:"byIdLink,omitempty"`
+	// Raw holds additional fields not mapped above.
+	Raw map[string]string `json:"raw,omitempty"`
+}
+
+// ParseNodeDisk maps a raw /disks/list entry onto a NodeDisk.
+func ParseNodeDisk(raw map[string]any) *NodeDisk {
+	d := &NodeDisk{OsdID: -1, Raw: make(map[string]string)}
+	for k, v := range raw {
+		s := fmt.Sprintf("%v", v)
+		switch k {
+		case "devpath":
+			d.DevPath = s
+		case "type":
+			d.Type = s
+		case "vendor":
+			d.Vendor = strings.TrimSpace(s)
+		case "model":
+			d.Model = s
+		case "serial":
+			d.Serial = s
+		case "wwn":
+			d.WWN = s
+		case "size":
+			d.Size, _ = strconv.ParseInt(toJSONNumber(v).String(), 10, 64)
+		case "rpm":
+			d.RPM = toInt(v)
+		case "health":
+			d.Health = s
+		case "wearout":
+			// PVE reports "N/A" when the device has no wearout indicator.
+			if n, err := strconv.Atoi(toJSONNumber(v).String()); err == nil {
+				d.Wearout = &n
+			}
+		case "used":
+			d.UsedBy = s
+		case "gpt":
+			d.GPT = toInt(v) == 1
+		case "mounted":
+			d.Mounted = toInt(v) == 1
+		case "osdid":
+			d.OsdID = toInt(v)
+		case "parent":
+			d.Parent = s
+		case "by_id_link":
+			d.ByIDLink = s
+		default:
+			d.Raw[k] = s
+		}
+	}
+	return d
+}
+
+// WearoutUsed returns the used life of the disk in percent, and false when
+// the disk does not report wearout.
+func (d *NodeDisk) WearoutUsed() (int, bool) {
+	if d.Wearout == nil {
+		return 0, false
+	}
+	return 100 - *d.Wearout, true
+}
+
+// Healthy reports whether SMART considers the disk healthy. Disks without
+// SMART data are not reported as unhealthy.
+func (d *NodeDisk) Healthy() bool {
+	switch strings.ToUpper(d.Health) {
+	case "", "PASSED", "OK", "UNKNOWN":
+		return true
+	}
+	return false
+}
+
+// DiskListFilter limits the disks returned by ListNodeDisks.
+type DiskListFilter struct {
+	Type              string // "unused" or "journal_disks"; empty lists all disks
+	IncludePartitions bool
+	SkipSmart         bool // Skip SMART checks, leaving Health and Wearout empty
+}
+
+// ListNodeDisks returns the block devices of node.
+func (c *Client) ListNodeDisks(ctx context.Context, node string, filter DiskListFilter) ([]NodeDisk, error) {
+	query := url.Values{}
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
+	}
+	if filter.IncludePartitions {
+		query.Set("include-partitions", "1")
+	}
+	if filter.SkipSmart {
+		query.Set("skipsmart", "1")
+	}
+	path := fmt.Sprintf("%s/%s/disks/list", apiNodesPath, url.PathEscape(node))
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var raw []map[string]any
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &raw); err != nil {
+		return nil, err
+	}
+
+	disks := make([]NodeDisk, 0, len(raw))
+	for _, r := range raw {
+		disks = append(disks, *ParseNodeDisk(r))
+	}
+	return disks, nil
+}
+
+// SmartAttribute is a single ATA SMART attribute.
+type SmartAttribute struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	Value      int     `json:"value"`
+	Worst      int     `json:"worst"`
+	Threshold  int     `json:"threshold"`
+	Flags      string  `json:"flags,omitempty"`
+	Fail       string  `json:"fail,omitempty"` // WHEN_FAILED: "-", "FAILING_NOW" or "In_the_past"
+	Raw        string  `json:"raw"`
+	Normalized float64 `json:"normalized"`
+}
+
+// Failing reports whether the attribute is currently below its threshold.
+func (a SmartAttribute) Failing() bool {
+	return a.Fail == "FAILING_NOW"
+}
+
+// SmartData is the decoded response of /nodes/{node}/disks/smart.
+type SmartData struct {
+	Health string `json:"health"`
+	// Type is "ata" for an attribute table, "text" for e.g. NVMe.
+	Type       string           `json:"type"`
+	Attributes []SmartAttribute `json:"attributes,omitempty"`
+	// Text is the smartctl output for Type "text", parsed into TextAttributes.
+	Text           string            `json:"text,omitempty"`
+	TextAttributes map[string]string `json:"textAttributes,omitempty"`
+}
+
+// smartTextLineRegex matches "Key: value" lines of smartctl output.
+var smartTextLineRegex = regexp.MustCompile(`^([A-Za-z][^:]*?):\s+(.+)$`)
+
+// Attribute returns the ATA attribute with the given name, or nil.
+func (s *SmartData) Attribute(name string) *SmartAttribute {
+	for i := range s.Attributes {
+		if s.Attributes[i].Name == name {
+			return &s.Attributes[i]
+		}
+	}
+	return nil
+}
+
+// FailingAttributes returns the attributes that are currently failing.
+func (s *SmartData) FailingAttributes() []SmartAttribute {
+	var failing []SmartAttribute
+	for _, a := range s.Attributes {
+		if a.Failing() {
+			failing = append(failing, a)
+		}
+	}
+	return failing
+}
+
+// parseSmartInt parses a possibly space-padded SMART table value.
+func parseSmartInt(v any) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(fmt.Sprintf("%v", v)))
+	return n
+}
+
+func parseSmartAttribute(raw map[string]any) SmartAttribute {
+	a := SmartAttribute{
+		ID:        parseSmartInt(raw["id"]),
+		Value:     parseSmartInt(raw["value"]),
+		Worst:     parseSmartInt(raw["worst"]),
+		Threshold: parseSmartInt(raw["threshold"]),
+	}
+	str := func(key string) string {
+		if v, ok := raw[key]; ok && v != nil {
+			return strings.TrimSpace(fmt.Sprintf("%v", v))
+		}
+		return ""
+	}
+	a.Name = str("name")
+	a.Flags = str("flags")
+	a.Fail = str("fail")
+	a.Raw = str("raw")
+	a.Normalized, _ = strconv.ParseFloat(str("normalized"), 64)
+	return a
+}
+
+// GetDiskSmart returns the SMART data of disk (a device path such as
+// "/dev/sda") on node. With healthOnly, only Health is filled in.
+func (c *Client) GetDiskSmart(ctx context.Context, node, disk string, healthOnly bool) (*SmartData, error) {
+	query := url.Values{}
+	query.Set("disk", disk)
+	if healthOnly {
+		query.Set("healthonly", "1")
+	}
+	path := fmt.Sprintf("%s/%s/disks/smart?%s", apiNodesPath, url.PathEscape(node), query.Encode())
+
+	var resp struct {
+		Health     string           `json:"health"`
+		Type       string           `json:"type"`
+		Attributes []map[string]any `json:"attributes"`
+		Text       string           `json:"text"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &resp); err != nil {
+		return nil, err
+	}
+
+	smart := &SmartData{Health: resp.Health, Type: resp.Type, Text: resp.Text}
+	for _, r := range resp.Attributes {
+		smart.Attributes = append(smart.Attributes, parseSmartAttribute(r))
+	}
+	if resp.Text != "" {
+		smart.TextAttributes = make(map[string]string)
+		for _, line := range strings.Split(resp.Text, "\n") {
+			if m := smartTextLineRegex.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				smart.TextAttributes[m[1]] = m[2]
+			}
+		}
+	}
+	return smart, nil
+}
+
+// DiskStorageOptions are the parameters for turning an unused disk into a
+// directory, LVM or LVM-thin storage.
+type DiskStorageOptions struct {
+	Name       string // Required: storage, volume group or thin pool name
+	Device     string // Required: block device, e.g. "/dev/sdb"
+	AddStorage bool   // Also add a storage definition for the new volume
+	Filesystem string // Directory only: "ext4" (default) or "xfs"
+}
+
+func (o *DiskStorageOptions) validate(dir bool) error {
+	verr := &ValidationError{Resource: "DiskStorageOptions"}
+	if o.Name == "" {
+		verr.Add("name", "required")
+	}
+	if o.Device == "" {
+		verr.Add("device", "required")
+	}
+	if o.Filesystem != "" {
+		if !dir {
+			verr.Add("filesystem", "only valid for directory storages")
+		} else if o.Filesystem != "ext4" && o.Filesystem != "xfs" {
+			verr.Add("filesystem", "must be ext4 or xfs, got %q", o.Filesystem)
+		}
+	}
+	return verr.Err()
+}
+
+func (o *DiskStorageOptions) toParams() url.Values {
+	params := url.Values{}
+	params.Set("name", o.Name)
+	params.Set("device", o.Device)
+	if o.AddStorage {
+		params.Set("add_storage", "1")
+	}
+	if o.Filesystem != "" {
+		params.Set("filesystem", o.Filesystem)
+	}
+	return params
+}
+
+func (c *Client) createDiskStorage(ctx context.Context, node, kind string, opts DiskStorageOptions) (*Task, error) {
+	if err := opts.validate(kind == "directory"); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("%s/%s/disks/%s", apiNodesPath, url.PathEscape(node), kind)
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, path, opts.toParams(), &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// CreateDiskDirectory formats a disk and mounts it as a directory storage.
+func (c *Client) CreateDiskDirectory(ctx context.Context, node string, opts DiskStorageOptions) (*Task, error) {
+	return c.createDiskStorage(ctx, node, "directory", opts)
+}
+
+// CreateDiskLvm creates an LVM volume group on a disk.
+func (c *Client) CreateDiskLvm(ctx context.Context, node string, opts DiskStorageOptions) (*Task, error) {
+	return c.createDiskStorage(ctx, node, "lvm", opts)
+}
+
+// CreateDiskLvmThin creates an LVM thin pool on a disk.
+func (c *Client) CreateDiskLvmThin(ctx context.Context, node string, opts DiskStorageOptions) (*Task, error) {
+	return c.createDiskStorage(ctx, node, "lvmthin", opts)
+}
+
+// ZFS RAID levels.
+var zfsRaidLevels = []string{"single", "mirror", "raid10", "raidz", "raidz2", "raidz3", "draid", "draid2", "draid3"}
+
+// ZfsPoolOptions are the parameters for creating a ZFS pool from disks.
+type ZfsPoolOptions struct {
+	Name        string   // Required: pool name
+	Devices     []string // Required: block devices, e.g. ["/dev/sdb", "/dev/sdc"]
+	RaidLevel   string   // Required: "single", "mirror", "raid10", "raidz", "raidz2", "raidz3" or "draid[23]"
+	Ashift      int      // Default 12
+	Compression string   // e.g. "on", "lz4", "zstd"; default "on"
+	AddStorage  bool     // Also add a zfspool storage definition
+}
+
+func (o *ZfsPoolOptions) validate() error {
+	verr := &ValidationError{Resource: "ZfsPoolOptions"}
+	if o.Name == "" {
+		verr.Add("name", "required")
+	}
+	if len(o.Devices) == 0 {
+		verr.Add("devices", "required")
+	}
+	minDevices := map[string]int{"single": 1, "mirror": 2, "raid10": 4, "raidz": 3, "raidz2": 4, "raidz3": 5}
+	switch {
+	case o.RaidLevel == "":
+		verr.Add("raidlevel", "required")
+	case !slices.Contains(zfsRaidLevels, o.RaidLevel):
+		verr.Add("raidlevel", "must be one of %s, got %q", strings.Join(zfsRaidLevels, ", "), o.RaidLevel)
+	case len(o.Devices) < minDevices[o.RaidLevel]:
+		verr.Add("devices", "%s needs at least %d devices, got %d", o.RaidLevel, minDevices[o.RaidLevel], len(o.Devices))
+	case o.RaidLevel == "single" && len(o.Devices) > 1:
+		verr.Add("devices", "single needs exactly one device, got %d", len(o.Devices))
+	case o.RaidLevel == "raid10" && len(o.Devices)%2 != 0:
+		verr.Add("devices", "raid10 needs an even number of devices, got %d", len(o.Devices))
+	}
+	if o.Ashift != 0 && (o.Ashift < 9 || o.Ashift > 16) {
+		verr.Add("ashift", "must be between 9 and 16, got %d", o.Ashift)
+	}
+	return verr.Err()
+}
+
+func (o *ZfsPoolOptions) toParams() url.Values {
+	params := url.Values{}
+	params.Set("name", o.Name)
+	params.Set("devices", strings.Join(o.Devices, ","))
+	params.Set("raidlevel", o.RaidLevel)
+	if o.Ashift != 0 {
+		params.Set("ashift", fmt.Sprintf("%d", o.Ashift))
+	}
+	if o.Compression != "" {
+		params.Set("compression", o.Compression)
+	}
+	if o.AddStorage {
+		params.Set("add_storage", "1")
+	}
+	return params
+}
+
+// CreateZfsPool creates a ZFS pool from disks on node.
+func (c *Client) CreateZfsPool(ctx context.Context, node string, opts ZfsPoolOptions) (*Task, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("%s/%s/disks/zfs", apiNodesPath, url.PathEscape(node))
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, path, opts.toParams(), &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
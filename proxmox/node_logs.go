@@ -0,0 +1,142 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// syslogTimeLayout is the time format /syslog accepts for since and until.
+const syslogTimeLayout = "2006-01-02 15:04:05"
+
+// SyslogFilter selects a page of the node syslog.
+type SyslogFilter struct {
+	Start   int // First line to return (0-based)
+	Limit   int // Maximum number of lines; PVE defaults to 50
+	Since   time.Time
+	Until   time.Time
+	Service string // systemd unit, e.g. ServiceCorosync
+}
+
+// SyslogPage is a page of the node syslog.
+type SyslogPage struct {
+	Lines []TaskLogLine `json:"lines"`
+	Total int           `json:"total"` // Number of lines matching the filter
+}
+
+// ReadSyslog returns a page of the syslog of node. Since and Until are
+// formatted in their own location and interpreted in the node's time zone.
+func (c *Client) ReadSyslog(ctx context.Context, node string, filter SyslogFilter) (*SyslogPage, error) {
+	query := url.Values{}
+	query.Set("start", fmt.Sprintf("%d", filter.Start))
+	if filter.Limit > 0 {
+		query.Set("limit", fmt.Sprintf("%d", filter.Limit))
+	}
+	if !filter.Since.IsZero() {
+		query.Set("since", filter.Since.Format(syslogTimeLayout))
+	}
+	if !filter.Until.IsZero() {
+		query.Set("until", filter.Until.Format(syslogTimeLayout))
+	}
+	if filter.Service != "" {
+		query.Set("service", filter.Service)
+	}
+	path := fmt.Sprintf("%s/%s/syslog?%s", apiNodesPath, url.PathEscape(node), query.Encode())
+
+	page := &SyslogPage{}
+	var attribs struct {
+		Total int `json:"total"`
+	}
+	if err := c.doAttribs(ctx, http.MethodGet, path, &page.Lines, &attribs); err != nil {
+		return nil, err
+	}
+	page.Total = attribs.Total
+	return page, nil
+}
+
+// JournalFilter selects entries of the node journal. Cursors take precedence
+// over times.
+type JournalFilter struct {
+	Since       time.Time
+	Until       time.Time
+	LastEntries int    // Return only the last n entries
+	StartCursor string // Return entries after this cursor
+	EndCursor   string // Return entries up to this cursor
+}
+
+// JournalPage is a set of journal entries and the cursors delimiting it.
+type JournalPage struct {
+	Lines       []string `json:"lines"`
+	StartCursor string   `json:"startCursor,omitempty"`
+	EndCursor   string   `json:"endCursor,omitempty"` // Pass as StartCursor to read newer entries
+}
+
+// ReadJournal returns entries of the systemd journal of node. If no entries
+// match, the page is empty and has no cursors.
+func (c *Client) ReadJournal(ctx context.Context, node string, filter JournalFilter) (*JournalPage, error) {
+	query := url.Values{}
+	if !filter.Since.IsZero() {
+		query.Set("since", fmt.Sprintf("%d", filter.Since.Unix()))
+	}
+	if !filter.Until.IsZero() {
+		query.Set("until", fmt.Sprintf("%d", filter.Until.Unix()))
+	}
+	if filter.LastEntries > 0 {
+		query.Set("lastentries", fmt.Sprintf("%d", filter.LastEntries))
+	}
+	if filter.StartCursor != "" {
+		query.Set("startcursor", filter.StartCursor)
+	}
+	if filter.EndCursor != "" {
+		query.Set("endcursor", filter.EndCursor)
+	}
+	path := fmt.Sprintf("%s/%s/journal", apiNodesPath, url.PathEscape(node))
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var lines []string
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &lines); err != nil {
+		return nil, err
+	}
+
+	// PVE frames the entries with the cursors of the first and last entry. An
+	// empty response means no entries matched, and leaves the cursors unset.
+	switch len(lines) {
+	case 0:
+		return &JournalPage{}, nil
+	case 1:
+		return nil, fmt.Errorf("journal of node %s: missing end cursor", node)
+	}
+	return &JournalPage{
+		StartCursor: lines[0],
+		EndCursor:   lines[len(lines)-1],
+		Lines:       lines[1 : len(lines)-1],
+	}, nil
+}
+
+// StreamJournal writes journal entries of node matching filter to w, then
+// follows the journal, polling every interval (the default poll interval if
+// non-positive), until ctx is done. If filter.Until or filter.EndCursor is
+// set, it returns after the matching entries have been written instead.
+func (c *Client) StreamJournal(ctx context.Context, node string, filter JournalFilter, w io.Writer, interval time.Duration) error {
+	follow := filter.Until.IsZero() && filter.EndCursor == ""
+	return poll(ctx, interval, func() (bool, error) {
+		page, err := c.ReadJournal(ctx, node, filter)
+		if err != nil {
+			return false, err
+		}
+		for _, line := range page.Lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return false, err
+			}
+		}
+		if page.EndCursor != "" {
+			filter = JournalFilter{StartCursor: page.EndCursor}
+		}
+		return !follow, nil
+	})
+}
@@ -0,0 +1,81 @@
+package proxmox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadJournal(t *testing.T) {
+	tests := []struct {
+		body    string
+		want    JournalPage
+		wantErr bool
+	}{
+		{body: `["s=1","a","b","s=3"]`, want: JournalPage{StartCursor: "s=1", EndCursor: "s=3", Lines: []string{"a", "b"}}},
+		{body: `["s=1","s=1"]`, want: JournalPage{StartCursor: "s=1", EndCursor: "s=1", Lines: []string{}}},
+		{body: `[]`, want: JournalPage{}},
+		{body: `["s=1"]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"data":%s}`, tt.body)
+		})
+		page, err := c.ReadJournal(context.Background(), "pve1", JournalFilter{})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.body, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if page.StartCursor != tt.want.StartCursor || page.EndCursor != tt.want.EndCursor || strings.Join(page.Lines, "|") != strings.Join(tt.want.Lines, "|") {
+			t.Errorf("%s: page = %+v, want %+v", tt.body, page, tt.want)
+		}
+	}
+}
+
+func TestStreamJournalFollowsCursor(t *testing.T) {
+	var cursors []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		cursors = append(cursors, r.URL.Query().Get("startcursor"))
+		switch len(cursors) {
+		case 1:
+			fmt.Fprint(w, `{"data":["s=1","first","s=2"]}`)
+		case 2:
+			fmt.Fprint(w, `{"data":[]}`)
+		default:
+			fmt.Fprint(w, `{"data":["s=2","second","s=3"]}`)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var out strings.Builder
+	w := writerFunc(func(p []byte) (int, error) {
+		out.Write(p)
+		if strings.Contains(out.String(), "second") {
+			cancel()
+		}
+		return len(p), nil
+	})
+
+	err := c.StreamJournal(ctx, "pve1", JournalFilter{LastEntries: 1}, w, time.Millisecond)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("StreamJournal: %v", err)
+	}
+	if got, want := out.String(), "first\nsecond\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if got, want := strings.Join(cursors, ","), ",s=2,s=2"; got != want {
+		t.Errorf("start cursors = %q, want %q", got, want)
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
@@ -0,0 +1,449 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// NetworkInterfaceType is the type of a node network interface.
+type NetworkInterfaceType string
+
+const (
+	NetworkBridge NetworkInterfaceType = "bridge"
+	NetworkBond   NetworkInterfaceType = "bond"
+	NetworkEth    NetworkInterfaceType = "eth" // Physical NIC
+	NetworkVlan   NetworkInterfaceType = "vlan"
+	NetworkAlias  NetworkInterfaceType = "alias"
+
+	// NetworkAnyBridge is only valid as a ListNodeNetwork filter and matches
+	// Linux and OVS bridges.
+	NetworkAnyBridge NetworkInterfaceType = "any_bridge"
+)
+
+// Bond modes supported by ifupdown2.
+var bondModes = []string{"balance-rr", "active-backup", "balance-xor", "broadcast", "802.3ad", "balance-tlb", "balance-alb"}
+
+// vlanIfaceRegex matches VLAN interface names that carry their raw device and
+// tag, e.g. "eno1.20" or "vmbr0.20".
+var vlanIfaceRegex = regexp.MustCompile(`^(\S+)\.(\d+)$`)
+
+// NodeNetworkInterface is an interface from /etc/network/interfaces of a node.
+type NodeNetworkInterface struct {
+	Iface     string               `json:"iface"`
+	Type      NetworkInterfaceType `json:"type"`
+	Method    string               `json:"method,omitempty"`  // "static", "manual" or "dhcp"
+	Method6   string               `json:"method6,omitempty"` // "static", "manual", "dhcp" or "auto"
+	Autostart bool                 `json:"autostart"`
+	Active    bool                 `json:"active"` // Read-only: interface is up
+	Exists    bool                 `json:"exists"` // Read-only: physical NIC is present
+	Comments  string               `json:"comments,omitempty"`
+	MTU       int                  `json:"mtu,omitempty"`
+
+	// IPv4 configuration. CIDR takes precedence over Address/Netmask.
+	CIDR    string `json:"cidr,omitempty"` // e.g. "10.0.0.5/24"
+	Address string `json:"address,omitempty"`
+	Netmask string `json:"netmask,omitempty"`
+	Gateway string `json:"gateway,omitempty"`
+
+	// IPv6 configuration. CIDR6 takes precedence over Address6/Netmask6.
+	CIDR6    string `json:"cidr6,omitempty"`
+	Address6 string `json:"address6,omitempty"`
+	Netmask6 string `json:"netmask6,omitempty"`
+	Gateway6 string `json:"gateway6,omitempty"`
+
+	// Bridges
+	BridgePorts     []string `json:"bridgePorts,omitempty"`
+	BridgeVlanAware bool     `json:"bridgeVlanAware,omitempty"`
+	BridgeVids      string   `json:"bridgeVids,omitempty"` // e.g. "2-4094" or "10 20 100-200"
+
+	// Bonds
+	Slaves             []string `json:"slaves,omitempty"`
+	BondMode           string   `json:"bondMode,omitempty"`
+	BondPrimary        string   `json:"bondPrimary,omitempty"`        // active-backup only
+	BondXmitHashPolicy string   `json:"bondXmitHashPolicy,omitempty"` // "layer2", "layer2+3" or "layer3+4"
+
+	// VLANs
+	VlanID        int    `json:"vlanId,omitempty"`
+	VlanRawDevice string `json:"vlanRawDevice,omitempty"`
+
+	// Raw holds additional fields not mapped above, e.g. OVS options.
+	Raw map[string]string `json:"raw,omitempty"`
+}
+
+// ParseNodeNetworkInterface maps a raw /nodes/{node}/network entry onto a NodeNetworkInterface.
+func ParseNodeNetworkInterface(raw map[string]any) *NodeNetworkInterface {
+	n := &NodeNetworkInterface{Raw: make(map[string]string)}
+	for k, v := range raw {
+		s := fmt.Sprintf("%v", v)
+		switch k {
+		case "iface":
+			n.Iface = s
+		case "type":
+			n.Type = NetworkInterfaceType(s)
+		case "method":
+			n.Method = s
+		case "method6":
+			n.Method6 = s
+		case "autostart":
+			n.Autostart = toInt(v) == 1
+		case "active":
+			n.Active = toInt(v) == 1
+		case "exists":
+			n.Exists = toInt(v) == 1
+		case "comments":
+			n.Comments = strings.TrimRight(s, "\n")
+		case "mtu":
+			n.MTU = toInt(v)
+		case "cidr":
+			n.CIDR = s
+		case "address":
+			n.Address = s
+		case "netmask":
+			n.Netmask = s
+		case "gateway":
+			n.Gateway = s
+		case "cidr6":
+			n.CIDR6 = s
+		case "address6":
+			n.Address6 = s
+		case "netmask6":
+			n.Netmask6 = s
+		case "gateway6":
+			n.Gateway6 = s
+		case "bridge_ports":
+			n.BridgePorts = strings.Fields(s)
+		case "bridge_vlan_aware":
+			n.BridgeVlanAware = toInt(v) == 1
+		case "bridge_vids":
+			n.BridgeVids = s
+		case "slaves":
+			n.Slaves = strings.Fields(s)
+		case "bond_mode":
+			n.BondMode = s
+		case "bond-primary", "bond_primary":
+			n.BondPrimary = s
+		case "bond_xmit_hash_policy":
+			n.BondXmitHashPolicy = s
+		case "vlan-id":
+			n.VlanID = toInt(v)
+		case "vlan-raw-device":
+			n.VlanRawDevice = s
+		case "families", "priority":
+			// Derived by PVE from the method fields and the file order.
+		default:
+			n.Raw[k] = s
+		}
+	}
+	return n
+}
+
+func (n *NodeNetworkInterface) validate(create bool) error {
+	verr := &ValidationError{Resource: "NodeNetworkInterface"}
+	if n.Iface == "" {
+		verr.Add("iface", "required")
+	}
+	switch n.Type {
+	case NetworkBridge, NetworkBond, NetworkVlan, NetworkAlias:
+	case NetworkEth:
+		if create {
+			verr.Add("type", "physical interfaces cannot be created, only updated")
+		}
+	case "":
+		verr.Add("type", "required")
+	default:
+		if !strings.HasPrefix(string(n.Type), "OVS") {
+			verr.Add("type", "unsupported interface type %q", n.Type)
+		}
+	}
+
+	validateIP := func(field, value string, v6 bool) {
+		ip := net.ParseIP(value)
+		if ip == nil || (ip.To4() == nil) != v6 {
+			verr.Add(field, "invalid address %q", value)
+		}
+	}
+	validateCIDR := func(field, value string, v6 bool) {
+		ip, _, err := net.ParseCIDR(value)
+		if err != nil || (ip.To4() == nil) != v6 {
+			verr.Add(field, "invalid CIDR %q", value)
+		}
+	}
+	if n.CIDR != "" {
+		validateCIDR("cidr", n.CIDR, false)
+	}
+	if n.Address != "" {
+		validateIP("address", n.Address, false)
+	}
+	if n.Gateway != "" {
+		validateIP("gateway", n.Gateway, false)
+		if n.CIDR == "" && n.Address == "" {
+			verr.Add("gateway", "requires an address")
+		}
+	}
+	if n.CIDR6 != "" {
+		validateCIDR("cidr6", n.CIDR6, true)
+	}
+	if n.Address6 != "" {
+		validateIP("address6", n.Address6, true)
+	}
+	if n.Gateway6 != "" {
+		validateIP("gateway6", n.Gateway6, true)
+		if n.CIDR6 == "" && n.Address6 == "" {
+			verr.Add("gateway6", "requires an address")
+		}
+	}
+	if n.MTU != 0 && (n.MTU < 1280 || n.MTU > 65520) {
+		verr.Add("mtu", "must be between 1280 and 65520, got %d", n.MTU)
+	}
+
+	if n.Type != NetworkBridge && (len(n.BridgePorts) > 0 || n.BridgeVlanAware || n.BridgeVids != "") {
+		verr.Add("bridge_ports", "bridge options are only valid for bridges")
+	}
+	if n.BridgeVids != "" && !n.BridgeVlanAware {
+		verr.Add("bridge_vids", "requires bridge_vlan_aware")
+	}
+
+	if n.Type == NetworkBond {
+		if len(n.Slaves) == 0 {
+			verr.Add("slaves", "a bond needs at least one slave")
+		}
+		if n.BondMode != "" && !slices.Contains(bondModes, n.BondMode) {
+			verr.Add("bond_mode", "must be one of %s, got %q", strings.Join(bondModes, ", "), n.BondMode)
+		}
+		if n.BondPrimary != "" && n.BondMode != "active-backup" {
+			verr.Add("bond-primary", "only valid for active-backup bonds")
+		}
+		if n.BondXmitHashPolicy != "" && n.BondMode != "balance-xor" && n.BondMode != "802.3ad" {
+			verr.Add("bond_xmit_hash_policy", "only valid for balance-xor and 802.3ad bonds")
+		}
+	} else if len(n.Slaves) > 0 || n.BondMode != "" || n.BondPrimary != "" || n.BondXmitHashPolicy != "" {
+		verr.Add("slaves", "bond options are only valid for bonds")
+	}
+
+	if n.Type == NetworkVlan {
+		if n.VlanID != 0 && (n.VlanID < 1 || n.VlanID > 4094) {
+			verr.Add("vlan-id", "must be between 1 and 4094, got %d", n.VlanID)
+		}
+		if m := vlanIfaceRegex.FindStringSubmatch(n.Iface); m != nil {
+			if n.VlanID != 0 && fmt.Sprintf("%d", n.VlanID) != m[2] {
+				verr.Add("vlan-id", "does not match the tag in interface name %s", n.Iface)
+			}
+			if n.VlanRawDevice != "" && n.VlanRawDevice != m[1] {
+				verr.Add("vlan-raw-device", "does not match the device in interface name %s", n.Iface)
+			}
+		} else if n.VlanID == 0 || n.VlanRawDevice == "" {
+			verr.Add("vlan-raw-device", "vlan-id and vlan-raw-device are required unless the name is <device>.<tag>")
+		}
+	} else if n.VlanID != 0 || n.VlanRawDevice != "" {
+		verr.Add("vlan-id", "VLAN options are only valid for VLAN interfaces")
+	}
+	return verr.Err()
+}
+
+// nodeNetworkOptionKeys are the options that can be removed from an interface.
+var nodeNetworkOptionKeys = []string{
+	"address", "netmask", "cidr", "gateway",
+	"address6", "netmask6", "cidr6", "gateway6",
+	"mtu", "comments",
+	"bridge_ports", "bridge_vlan_aware", "bridge_vids",
+	"slaves", "bond_mode", "bond-primary", "bond_xmit_hash_policy",
+	"vlan-id", "vlan-raw-device",
+	"ovs_bridge", "ovs_bonds", "ovs_options", "ovs_ports", "ovs_tag",
+}
+
+// nodeNetworkReadOnlyKeys are returned by /nodes/{node}/network but cannot be set.
+var nodeNetworkReadOnlyKeys = map[string]bool{
+	"options":  true,
+	"options6": true,
+}
+
+func (n *NodeNetworkInterface) toParams(create bool) url.Values {
+	params := url.Values{}
+	if create {
+		params.Set("iface", n.Iface)
+	}
+	params.Set("type", string(n.Type))
+	params.Set("autostart", formatPveBool(n.Autostart))
+
+	set := func(key, v string) {
+		if v != "" {
+			params.Set(key, v)
+		}
+	}
+	if n.CIDR != "" {
+		params.Set("cidr", n.CIDR)
+	} else {
+		set("address", n.Address)
+		set("netmask", n.Netmask)
+	}
+	set("gateway", n.Gateway)
+	if n.CIDR6 != "" {
+		params.Set("cidr6", n.CIDR6)
+	} else {
+		set("address6", n.Address6)
+		set("netmask6", n.Netmask6)
+	}
+	set("gateway6", n.Gateway6)
+	if n.MTU != 0 {
+		params.Set("mtu", fmt.Sprintf("%d", n.MTU))
+	}
+	set("comments", n.Comments)
+
+	set("bridge_ports", strings.Join(n.BridgePorts, " "))
+	if n.BridgeVlanAware {
+		params.Set("bridge_vlan_aware", "1")
+	}
+	set("bridge_vids", n.BridgeVids)
+
+	set("slaves", strings.Join(n.Slaves, " "))
+	set("bond_mode", n.BondMode)
+	set("bond-primary", n.BondPrimary)
+	set("bond_xmit_hash_policy", n.BondXmitHashPolicy)
+
+	if n.VlanID != 0 {
+		params.Set("vlan-id", fmt.Sprintf("%d", n.VlanID))
+	}
+	set("vlan-raw-device", n.VlanRawDevice)
+	for k, v := range n.Raw {
+		if _, ok := params[k]; !ok && !nodeNetworkReadOnlyKeys[k] {
+			set(k, v)
+		}
+	}
+
+	// Address and netmask are derived from cidr (and vice versa), so they are
+	// kept if either is set.
+	if !create {
+		deleteUnset(params, nodeNetworkOptionKeys, func(k string) bool {
+			switch k {
+			case "address", "netmask", "cidr":
+				return n.CIDR != "" || n.Address != ""
+			case "address6", "netmask6", "cidr6":
+				return n.CIDR6 != "" || n.Address6 != ""
+			}
+			return false
+		})
+	}
+	return params
+}
+
+// NodeNetworkConfig is the network configuration of a node, including changes
+// that have been staged but not applied yet.
+type NodeNetworkConfig struct {
+	Interfaces []NodeNetworkInterface `json:"interfaces"`
+	// Changes is a diff between the running /etc/network/interfaces and the
+	// pending configuration. It is empty when nothing is pending.
+	Changes string `json:"changes,omitempty"`
+}
+
+// HasPendingChanges reports whether the node has staged changes that
+// ApplyNodeNetwork would activate.
+func (c *NodeNetworkConfig) HasPendingChanges() bool {
+	return c.Changes != ""
+}
+
+// Interface returns the interface named iface, or nil.
+func (c *NodeNetworkConfig) Interface(iface string) *NodeNetworkInterface {
+	for i := range c.Interfaces {
+		if c.Interfaces[i].Iface == iface {
+			return &c.Interfaces[i]
+		}
+	}
+	return nil
+}
+
+func nodeNetworkPath(node string) string {
+	return fmt.Sprintf("%s/%s/network", apiNodesPath, url.PathEscape(node))
+}
+
+// ListNodeNetwork returns the network interfaces of node as they will be after
+// pending changes are applied, along with the pending diff. An empty typ
+// returns all interfaces.
+func (c *Client) ListNodeNetwork(ctx context.Context, node string, typ NetworkInterfaceType) (*NodeNetworkConfig, error) {
+	path := nodeNetworkPath(node)
+	if typ != "" {
+		path += "?type=" + url.QueryEscape(string(typ))
+	}
+
+	var raw []map[string]any
+	var attribs struct {
+		Changes string `json:"changes"`
+	}
+	if err := c.doAttribs(ctx, http.MethodGet, path, &raw, &attribs); err != nil {
+		return nil, err
+	}
+
+	cfg := &NodeNetworkConfig{Changes: attribs.Changes, Interfaces: make([]NodeNetworkInterface, 0, len(raw))}
+	for _, r := range raw {
+		cfg.Interfaces = append(cfg.Interfaces, *ParseNodeNetworkInterface(r))
+	}
+	sort.Slice(cfg.Interfaces, func(i, j int) bool {
+		return compareNatural(cfg.Interfaces[i].Iface, cfg.Interfaces[j].Iface) < 0
+	})
+	return cfg, nil
+}
+
+// GetNodeNetworkInterface returns a single interface of node.
+func (c *Client) GetNodeNetworkInterface(ctx context.Context, node, iface string) (*NodeNetworkInterface, error) {
+	var raw map[string]any
+	if err := c.do(ctx, http.MethodGet, nodeNetworkPath(node)+"/"+url.PathEscape(iface), nil, nil, false, &raw); err != nil {
+		return nil, err
+	}
+	n := ParseNodeNetworkInterface(raw)
+	if n.Iface == "" {
+		n.Iface = iface
+	}
+	return n, nil
+}
+
+// CreateNodeNetworkInterface stages a new bridge, bond, VLAN or alias on node.
+// The change takes effect after ApplyNodeNetwork.
+func (c *Client) CreateNodeNetworkInterface(ctx context.Context, node string, iface *NodeNetworkInterface) error {
+	if iface == nil {
+		return fmt.Errorf("NodeNetworkInterface is required")
+	}
+	if err := iface.validate(true); err != nil {
+		return err
+	}
+	return c.doForm(ctx, http.MethodPost, nodeNetworkPath(node), iface.toParams(true), nil)
+}
+
+// UpdateNodeNetworkInterface stages iface as the new configuration of the
+// interface with the same name. Options that are not set on iface are
+// removed. The change takes effect after ApplyNodeNetwork.
+func (c *Client) UpdateNodeNetworkInterface(ctx context.Context, node string, iface *NodeNetworkInterface) error {
+	if iface == nil {
+		return fmt.Errorf("NodeNetworkInterface is required")
+	}
+	if err := iface.validate(false); err != nil {
+		return err
+	}
+	return c.doForm(ctx, http.MethodPut, nodeNetworkPath(node)+"/"+url.PathEscape(iface.Iface), iface.toParams(false), nil)
+}
+
+// DeleteNodeNetworkInterface stages the removal of iface. The change takes
+// effect after ApplyNodeNetwork.
+func (c *Client) DeleteNodeNetworkInterface(ctx context.Context, node, iface string) error {
+	return c.do(ctx, http.MethodDelete, nodeNetworkPath(node)+"/"+url.PathEscape(iface), nil, nil, true, nil)
+}
+
+// ApplyNodeNetwork activates the pending network configuration of node
+// (ifreload). The returned task reports whether the reload succeeded.
+func (c *Client) ApplyNodeNetwork(ctx context.Context, node string) (*Task, error) {
+	var upid string
+	if err := c.do(ctx, http.MethodPut, nodeNetworkPath(node), nil, nil, true, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// RevertNodeNetwork discards the pending network changes of node.
+func (c *Client) RevertNodeNetwork(ctx context.Context, node string) error {
+	return c.do(ctx, http.MethodDelete, nodeNetworkPath(node), nil, nil, true, nil)
+}
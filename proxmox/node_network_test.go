@@ -0,0 +1,37 @@
+package proxmox
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNodeNetworkInterfaceOVSParams(t *testing.T) {
+	n := ParseNodeNetworkInterface(map[string]any{
+		"iface":       "vmbr1",
+		"type":        "OVSBridge",
+		"autostart":   1,
+		"ovs_ports":   "bond0 vlan10",
+		"ovs_options": "stp_enable=true",
+		"options":     []any{"post-up true"},
+		"families":    []any{"inet"},
+	})
+
+	create := n.toParams(true)
+	for k, want := range map[string]string{"ovs_ports": "bond0 vlan10", "ovs_options": "stp_enable=true"} {
+		if got := create.Get(k); got != want {
+			t.Errorf("create %s = %q, want %q", k, got, want)
+		}
+	}
+	for _, k := range []string{"options", "families"} {
+		if create.Has(k) {
+			t.Errorf("create sends read-only %s", k)
+		}
+	}
+
+	delete(n.Raw, "ovs_options")
+	deleted := strings.Split(n.toParams(false).Get("delete"), ",")
+	if !slices.Contains(deleted, "ovs_options") || slices.Contains(deleted, "ovs_ports") {
+		t.Errorf("update deletes %v, want ovs_options but not ovs_ports", deleted)
+	}
+}
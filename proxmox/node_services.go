@@ -0,0 +1,94 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Names of the PVE services most relevant to cluster health.
+const (
+	ServicePveProxy   = "pveproxy"
+	ServicePveDaemon  = "pvedaemon"
+	ServiceCorosync   = "corosync"
+	ServicePveCluster = "pve-cluster"
+	ServicePveStatd   = "pvestatd"
+)
+
+// NodeService is a systemd service PVE manages on a node.
+type NodeService struct {
+	Service     string `json:"service"`
+	Name        string `json:"name"`
+	Desc        string `json:"desc"`
+	State       string `json:"state"`        // e.g. "running" or "dead"
+	ActiveState string `json:"active-state"` // e.g. "active", "inactive" or "failed"
+	UnitState   string `json:"unit-state"`   // e.g. "enabled", "disabled" or "masked"
+}
+
+// IsRunning reports whether the service is running.
+func (s *NodeService) IsRunning() bool {
+	return s.State == "running"
+}
+
+// Failed reports whether systemd considers the service failed.
+func (s *NodeService) Failed() bool {
+	return s.ActiveState == "failed"
+}
+
+func nodeServicePath(node, service, sub string) string {
+	return fmt.Sprintf("%s/%s/services/%s%s", apiNodesPath, url.PathEscape(node), url.PathEscape(service), sub)
+}
+
+// ListNodeServices returns the PVE-managed services of node.
+func (c *Client) ListNodeServices(ctx context.Context, node string) ([]NodeService, error) {
+	path := fmt.Sprintf("%s/%s/services", apiNodesPath, url.PathEscape(node))
+
+	var services []NodeService
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// GetNodeService returns the state of a single service on node.
+func (c *Client) GetNodeService(ctx context.Context, node, service string) (*NodeService, error) {
+	var s NodeService
+	if err := c.do(ctx, http.MethodGet, nodeServicePath(node, service, "/state"), nil, nil, false, &s); err != nil {
+		return nil, err
+	}
+	if s.Service == "" {
+		s.Service = service
+	}
+	return &s, nil
+}
+
+func (c *Client) nodeServiceCommand(ctx context.Context, node, service, command string) (*Task, error) {
+	var upid string
+	if err := c.do(ctx, http.MethodPost, nodeServicePath(node, service, "/"+command), nil, nil, true, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// StartNodeService starts service on node.
+func (c *Client) StartNodeService(ctx context.Context, node, service string) (*Task, error) {
+	return c.nodeServiceCommand(ctx, node, service, "start")
+}
+
+// StopNodeService stops service on node. Stopping pveproxy or pve-cluster
+// on the node the client talks to makes the API unreachable.
+func (c *Client) StopNodeService(ctx context.Context, node, service string) (*Task, error) {
+	return c.nodeServiceCommand(ctx, node, service, "stop")
+}
+
+// RestartNodeService restarts service on node.
+func (c *Client) RestartNodeService(ctx context.Context, node, service string) (*Task, error) {
+	return c.nodeServiceCommand(ctx, node, service, "restart")
+}
+
+// ReloadNodeService reloads the configuration of service on node, which for
+// pveproxy and pvedaemon keeps existing connections open.
+func (c *Client) ReloadNodeService(ctx context.Context, node, service string) (*Task, error) {
+	return c.nodeServiceCommand(ctx, node, service, "reload")
+}
@@ -0,0 +1,137 @@
+package proxmox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// NodeCPUInfo describes the CPUs of a node.
+type NodeCPUInfo struct {
+	Model   string      `json:"model"`
+	CPUs    int         `json:"cpus"`
+	Cores   int         `json:"cores"`
+	Sockets int         `json:"sockets"`
+	MHz     json.Number `json:"mhz"`
+	HVM     PveBool     `json:"hvm"`
+	Flags   string      `json:"flags,omitempty"`
+	UserHz  int         `json:"user_hz,omitempty"`
+}
+
+// NodeMemoryUsage is memory, swap or root file system usage in bytes.
+type NodeMemoryUsage struct {
+	Total int64 `json:"total"`
+	Used  int64 `json:"used"`
+	Free  int64 `json:"free"`
+	Avail int64 `json:"avail,omitempty"` // rootfs only
+}
+
+// NodeBootInfo describes how the node booted.
+type NodeBootInfo struct {
+	Mode       string  `json:"mode"` // "efi" or "legacy-bios"
+	SecureBoot PveBool `json:"secureboot,omitempty"`
+}
+
+// NodeKernel is the uname information of the running kernel.
+type NodeKernel struct {
+	Sysname string `json:"sysname"`
+	Release string `json:"release"`
+	Version string `json:"version"`
+	Machine string `json:"machine"`
+}
+
+// NodeStatus is the decoded response of /nodes/{node}/status.
+type NodeStatus struct {
+	CPU     float64         `json:"cpu"`
+	Wait    float64         `json:"wait,omitempty"` // IO wait fraction
+	Idle    float64         `json:"idle,omitempty"`
+	LoadAvg []json.Number   `json:"loadavg"` // 1, 5 and 15 minute load
+	Uptime  int64           `json:"uptime"`
+	CPUInfo NodeCPUInfo     `json:"cpuinfo"`
+	Memory  NodeMemoryUsage `json:"memory"`
+	Swap    NodeMemoryUsage `json:"swap"`
+	RootFs  NodeMemoryUsage `json:"rootfs"`
+	Ksm     struct {
+		Shared int64 `json:"shared"`
+	} `json:"ksm"`
+	KVersion      string       `json:"kversion"`
+	CurrentKernel *NodeKernel  `json:"current-kernel,omitempty"`
+	PveVersion    string       `json:"pveversion"`
+	BootInfo      NodeBootInfo `json:"boot-info"`
+}
+
+// GetNodeStatus returns the CPU, memory, load and version status of node.
+func (c *Client) GetNodeStatus(ctx context.Context, node string) (*NodeStatus, error) {
+	path := fmt.Sprintf("%s/%s/status", apiNodesPath, url.PathEscape(node))
+
+	var status NodeStatus
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// nodePowerCommand sends a reboot or shutdown command to node. confirmNode must
+// repeat the node name so a wrong variable cannot take down another host.
+func (c *Client) nodePowerCommand(ctx context.Context, node, confirmNode, command string) error {
+	if node == "" || confirmNode != node {
+		return fmt.Errorf("refusing to %s node %q: confirmation %q does not match", command, node, confirmNode)
+	}
+
+	params := url.Values{}
+	params.Set("command", command)
+	path := fmt.Sprintf("%s/%s/status", apiNodesPath, url.PathEscape(node))
+	return c.doForm(ctx, http.MethodPost, path, params, nil)
+}
+
+// RebootNode reboots the node itself. confirmNode must equal node.
+func (c *Client) RebootNode(ctx context.Context, node, confirmNode string) error {
+	return c.nodePowerCommand(ctx, node, confirmNode, "reboot")
+}
+
+// ShutdownNode powers off the node itself. confirmNode must equal node.
+func (c *Client) ShutdownNode(ctx context.Context, node, confirmNode string) error {
+	return c.nodePowerCommand(ctx, node, confirmNode, "shutdown")
+}
+
+// NodeVersion is the decoded response of /nodes/{node}/version.
+type NodeVersion struct {
+	Version string `json:"version"` // e.g. "8.2.7"
+	Release string `json:"release"` // e.g. "8.2"
+	RepoID  string `json:"repoid"`
+}
+
+// GetNodeVersion returns the PVE version running on node.
+func (c *Client) GetNodeVersion(ctx context.Context, node string) (*NodeVersion, error) {
+	path := fmt.Sprintf("%s/%s/version", apiNodesPath, url.PathEscape(node))
+
+	var version NodeVersion
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// NodesBehind returns the nodes whose version is older than the newest version
+// in versions, sorted by name. Nil entries, e.g. for nodes that did not
+// answer, are skipped.
+func NodesBehind(versions map[string]*NodeVersion) []string {
+	newest := ""
+	for _, v := range versions {
+		if v != nil && compareNatural(v.Version, newest) > 0 {
+			newest = v.Version
+		}
+	}
+
+	var behind []string
+	for node, v := range versions {
+		if v != nil && compareNatural(v.Version, newest) < 0 {
+			behind = append(behind, node)
+		}
+	}
+	sort.Strings(behind)
+	return behind
+}
@@ -0,0 +1,18 @@
+package proxmox
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNodesBehind(t *testing.T) {
+	got := NodesBehind(map[string]*NodeVersion{
+		"pve1": {Version: "8.2.4"},
+		"pve2": {Version: "8.2.10"},
+		"pve3": nil,
+		"pve4": {Version: "8.1.4"},
+	})
+	if want := []string{"pve1", "pve4"}; !slices.Equal(got, want) {
+		t.Errorf("NodesBehind = %v, want %v", got, want)
+	}
+}
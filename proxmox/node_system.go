@@ -0,0 +1,182 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// NodeDNS is the resolver configuration of a node.
+type NodeDNS struct {
+	Search string `json:"search"` // Search domain
+	DNS1   string `json:"dns1,omitempty"`
+	DNS2   string `json:"dns2,omitempty"`
+	DNS3   string `json:"dns3,omitempty"`
+}
+
+// Servers returns the configured name servers in order.
+func (d *NodeDNS) Servers() []string {
+	var servers []string
+	for _, s := range []string{d.DNS1, d.DNS2, d.DNS3} {
+		if s != "" {
+			servers = append(servers, s)
+		}
+	}
+	return servers
+}
+
+// Equal reports whether d and other configure the same search domain and
+// name servers in the same order.
+func (d *NodeDNS) Equal(other *NodeDNS) bool {
+	return d.Search == other.Search && slices.Equal(d.Servers(), other.Servers())
+}
+
+func (d *NodeDNS) validate() error {
+	verr := &ValidationError{Resource: "NodeDNS"}
+	if d.Search == "" {
+		verr.Add("search", "required")
+	}
+	for i, s := range []string{d.DNS1, d.DNS2, d.DNS3} {
+		if s != "" && net.ParseIP(s) == nil {
+			verr.Add(fmt.Sprintf("dns%d", i+1), "invalid IP address %q", s)
+		}
+	}
+	return verr.Err()
+}
+
+// GetNodeDNS returns the resolver configuration of node.
+func (c *Client) GetNodeDNS(ctx context.Context, node string) (*NodeDNS, error) {
+	var dns NodeDNS
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s/dns", apiNodesPath, url.PathEscape(node)), nil, nil, false, &dns); err != nil {
+		return nil, err
+	}
+	return &dns, nil
+}
+
+// SetNodeDNS replaces the resolver configuration of node. Name servers that
+// are not set are removed.
+func (c *Client) SetNodeDNS(ctx context.Context, node string, dns NodeDNS) error {
+	if err := dns.validate(); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("search", dns.Search)
+	for i, s := range dns.Servers() {
+		params.Set(fmt.Sprintf("dns%d", i+1), s)
+	}
+	return c.doForm(ctx, http.MethodPut, fmt.Sprintf("%s/%s/dns", apiNodesPath, url.PathEscape(node)), params, nil)
+}
+
+// NodeHosts is the /etc/hosts file of a node.
+type NodeHosts struct {
+	Data string `json:"data"`
+	// Digest identifies the version of the file that was read. Passing it
+	// back to SetNodeHosts makes the update fail if the file changed since.
+	Digest string `json:"digest"`
+}
+
+// HostsEntry is an address line of a hosts file.
+type HostsEntry struct {
+	IP    string   `json:"ip"`
+	Names []string `json:"names"`
+}
+
+// Entries returns the address lines of the hosts file, without comments.
+func (h *NodeHosts) Entries() []HostsEntry {
+	var entries []HostsEntry
+	for _, line := range strings.Split(h.Data, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		entries = append(entries, HostsEntry{IP: fields[0], Names: fields[1:]})
+	}
+	return entries
+}
+
+// Lookup returns the addresses name is mapped to.
+func (h *NodeHosts) Lookup(name string) []string {
+	var ips []string
+	for _, e := range h.Entries() {
+		if slices.Contains(e.Names, name) {
+			ips = append(ips, e.IP)
+		}
+	}
+	return ips
+}
+
+// GetNodeHosts returns the /etc/hosts file of node.
+func (c *Client) GetNodeHosts(ctx context.Context, node string) (*NodeHosts, error) {
+	var hosts NodeHosts
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s/hosts", apiNodesPath, url.PathEscape(node)), nil, nil, false, &hosts); err != nil {
+		return nil, err
+	}
+	return &hosts, nil
+}
+
+// SetNodeHosts writes hosts.Data to /etc/hosts of node. If hosts.Digest is
+// set, the write fails if the file was changed after it was read.
+func (c *Client) SetNodeHosts(ctx context.Context, node string, hosts NodeHosts) error {
+	params := url.Values{}
+	params.Set("data", hosts.Data)
+	if hosts.Digest != "" {
+		params.Set("digest", hosts.Digest)
+	}
+	return c.doForm(ctx, http.MethodPost, fmt.Sprintf("%s/%s/hosts", apiNodesPath, url.PathEscape(node)), params, nil)
+}
+
+// NodeTime is the time configuration and clock of a node.
+type NodeTime struct {
+	Timezone string `json:"timezone"` // e.g. "Europe/Vienna"
+	// Time is the node's clock as Unix time. LocalTime is the same instant
+	// shifted by the node's UTC offset, as PVE reports it.
+	Time      int64 `json:"time"`
+	LocalTime int64 `json:"localtime"`
+}
+
+// Location loads the node's time zone.
+func (t *NodeTime) Location() (*time.Location, error) {
+	return time.LoadLocation(t.Timezone)
+}
+
+// UTCOffset returns the node's current offset from UTC.
+func (t *NodeTime) UTCOffset() time.Duration {
+	return time.Duration(t.LocalTime-t.Time) * time.Second
+}
+
+// ClockSkew returns how far the node's clock is ahead of now (negative if
+// behind), at the one second resolution PVE reports.
+func (t *NodeTime) ClockSkew(now time.Time) time.Duration {
+	return time.Unix(t.Time, 0).Sub(now.Truncate(time.Second))
+}
+
+// GetNodeTime returns the time zone and clock of node.
+func (c *Client) GetNodeTime(ctx context.Context, node string) (*NodeTime, error) {
+	var t NodeTime
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s/time", apiNodesPath, url.PathEscape(node)), nil, nil, false, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SetNodeTimezone sets the time zone of node, e.g. "UTC" or "Europe/Vienna".
+func (c *Client) SetNodeTimezone(ctx context.Context, node, timezone string) error {
+	verr := &ValidationError{Resource: "NodeTime"}
+	if timezone == "" {
+		verr.Add("timezone", "required")
+	}
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("timezone", timezone)
+	return c.doForm(ctx, http.MethodPut, fmt.Sprintf("%s/%s/time", apiNodesPath, url.PathEscape(node)), params, nil)
+}
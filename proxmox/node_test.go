@@ -0,0 +1,64 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestGetNodeInventoryTicketAuth runs the inventory calls against a password
+// client that keeps logging in, so -race catches unsynchronized ticket reads.
+func TestGetNodeInventoryTicketAuth(t *testing.T) {
+	var mu sync.Mutex
+	var qemuPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api2/json/access/ticket":
+			fmt.Fprint(w, `{"data":{"ticket":"PVE:root@pam:TICKET","CSRFPreventionToken":"csrf"}}`)
+		case strings.HasSuffix(r.URL.Path, "/qemu"):
+			mu.Lock()
+			qemuPath = r.URL.EscapedPath()
+			mu.Unlock()
+			fmt.Fprint(w, `{"data":[{"vmid":100,"name":"vm100","status":"running"}]}`)
+		default:
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := newClient(srv.URL, "root@pam", "secret", AuthPassword, true)
+	if err != nil {
+		t.Fatalf("newClient: %v", err)
+	}
+	c.loginExpiry = 0 // every Login refreshes the ticket
+
+	ctx := context.Background()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			if err := c.Login(ctx); err != nil {
+				t.Errorf("Login: %v", err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		inv, err := c.GetNodeInventory(ctx, "pve/1")
+		if err != nil {
+			t.Fatalf("GetNodeInventory: %v", err)
+		}
+		if len(inv.QemuVMs) != 1 {
+			t.Errorf("QemuVMs = %+v, want one VM", inv.QemuVMs)
+		}
+	}
+	<-done
+
+	if want := "/api2/json/nodes/pve%2F1/qemu"; qemuPath != want {
+		t.Errorf("ListVMs requested %q, want %q", qemuPath, want)
+	}
+}
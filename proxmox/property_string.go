@@ -0,0 +1,205 @@
+package proxmox
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// propertyString is a decoded PVE property string such as
+// "name=eth0,bridge=vmbr0,ip=dhcp". A leading value without a key is stored
+// under the format's default key.
+type propertyString map[string]string
+
+// parsePropertyString splits s into key/value pairs. defaultKey names the key
+// used for a value without "key=" (e.g. the volume of a mount point); pass ""
+// if the format has none.
+func parsePropertyString(s, defaultKey string) (propertyString, error) {
+	props := make(propertyString)
+	if strings.TrimSpace(s) == "" {
+		return props, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			if defaultKey == "" {
+				return nil, fmt.Errorf("property %q has no key", part)
+			}
+			k, v = defaultKey, part
+		}
+		k = strings.TrimSpace(k)
+		if _, dup := props[k]; dup {
+			return nil, fmt.Errorf("duplicate property %q", k)
+		}
+		props[k] = v
+	}
+	return props, nil
+}
+
+// take removes key from p and returns its value.
+func (p propertyString) take(key string) string {
+	v := p[key]
+	delete(p, key)
+	return v
+}
+
+// takeInt removes key from p and parses it as an int. A missing key yields 0.
+func (p propertyString) takeInt(key string) (int, error) {
+	v := p.take(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return n, nil
+}
+
+// takeFloat removes key from p and parses it as a float64. A missing key yields 0.
+func (p propertyString) takeFloat(key string) (float64, error) {
+	v := p.take(key)
+	if v == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return f, nil
+}
+
+// takeBool removes key from p and parses it as a PVE boolean. A missing key
+// yields nil so that "unset" and "0" survive a round trip.
+func (p propertyString) takeBool(key string) (*bool, error) {
+	v, ok := p[key]
+	if !ok {
+		return nil, nil
+	}
+	delete(p, key)
+	b, err := parsePveBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return &b, nil
+}
+
+// parsePveBool accepts the boolean spellings PVE understands.
+func parsePveBool(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "1", "yes", "on", "true":
+		return true, nil
+	case "0", "no", "off", "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", v)
+}
+
+// formatPveBool formats b the way PVE returns booleans.
+func formatPveBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// deleteUnset adds the keys that params does not set to its delete list, so an
+// update replaces the existing options instead of merging into them. keep, if
+// non-nil, exempts keys that must not be deleted although they are unset.
+func deleteUnset(params url.Values, keys []string, keep func(key string) bool) {
+	var unset []string
+	for _, k := range keys {
+		if _, set := params[k]; set || (keep != nil && keep(k)) {
+			continue
+		}
+		unset = append(unset, k)
+	}
+	if len(unset) > 0 {
+		params.Set("delete", strings.Join(unset, ","))
+	}
+}
+
+// propertyStringBuilder assembles a property string, skipping unset values.
+type propertyStringBuilder struct {
+	parts []string
+}
+
+func (b *propertyStringBuilder) value(v string) {
+	if v != "" {
+		b.parts = append(b.parts, v)
+	}
+}
+
+func (b *propertyStringBuilder) str(key, v string) {
+	if v != "" {
+		b.parts = append(b.parts, key+"="+v)
+	}
+}
+
+func (b *propertyStringBuilder) int(key string, v int) {
+	if v != 0 {
+		b.parts = append(b.parts, key+"="+strconv.Itoa(v))
+	}
+}
+
+func (b *propertyStringBuilder) float(key string, v float64) {
+	if v != 0 {
+		b.parts = append(b.parts, key+"="+strconv.FormatFloat(v, 'f', -1, 64))
+	}
+}
+
+func (b *propertyStringBuilder) bool(key string, v *bool) {
+	if v != nil {
+		b.parts = append(b.parts, key+"="+formatPveBool(*v))
+	}
+}
+
+// extra appends any unrecognised options in key order.
+func (b *propertyStringBuilder) extra(opts map[string]string) {
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.str(k, opts[k])
+	}
+}
+
+func (b *propertyStringBuilder) String() string {
+	return strings.Join(b.parts, ",")
+}
+
+// remaining returns the options left in p after the known keys were taken, or
+// nil if there are none.
+func (p propertyString) remaining() map[string]string {
+	if len(p) == 0 {
+		return nil
+	}
+	return map[string]string(p)
+}
+
+// BoolPtr returns a pointer to b, for optional boolean fields.
+func BoolPtr(b bool) *bool {
+	return &b
+}
+
+// PveBool decodes the 0/1 integers, strings and JSON booleans PVE uses for flags.
+type PveBool bool
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *PveBool) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		*b = false
+		return nil
+	}
+	v, err := parsePveBool(s)
+	if err != nil {
+		return err
+	}
+	*b = PveBool(v)
+	return nil
+}
@@ -2,10 +2,13 @@ package proxmox
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -64,3 +67,317 @@ func (l *LxcContainer) ParseSshPublicKeySlice() (string, error) {
 	sshKeysParam.WriteString(lastSshKeyItem)
 	return sshKeysParam.String(), nil
 }
+
+// ParseLxcContainerConfig maps a raw /lxc/{vmid}/config response onto an LxcContainer.
+func ParseLxcContainerConfig(raw map[string]any) *LxcContainer {
+	lxc := &LxcContainer{Raw: make(map[string]string)}
+	for k, v := range raw {
+		switch k {
+		case "hostname":
+			lxc.Hostname = fmt.Sprintf("%v", v)
+		case "rootfs":
+			lxc.Storage, lxc.RootFsSize = parseLxcRootFs(fmt.Sprintf("%v", v))
+		case "memory":
+			lxc.Memory = toInt(v)
+		case "swap":
+			lxc.Swap = toInt(v)
+		case "cores":
+			lxc.Cores = toInt(v)
+		case "cpulimit":
+			lxc.CpuLimit = toInt(v)
+		case "cpuunits":
+			lxc.CpuUnits = toInt(v)
+		case "nameserver":
+			lxc.Nameserver = fmt.Sprintf("%v", v)
+		case "searchdomain":
+			lxc.Searchdomain = fmt.Sprintf("%v", v)
+		case "description":
+			lxc.Description = fmt.Sprintf("%v", v)
+		case "unprivileged":
+			lxc.Unprivileged = toInt(v) == 1
+		case "arch":
+			lxc.Arch = fmt.Sprintf("%v", v)
+		case "cmode":
+			lxc.Cmode = fmt.Sprintf("%v", v)
+		case "console":
+			console := toInt(v) == 1
+			lxc.Console = &console
+		case "debug":
+			lxc.Debug = toInt(v)
+		case "features":
+			features, err := ParseLxcFeatures(fmt.Sprintf("%v", v))
+			if err != nil {
+				lxc.Raw[k] = fmt.Sprintf("%v", v)
+				continue
+			}
+			lxc.Features = features
+		case "startup":
+			lxc.Startup = fmt.Sprintf("%v", v)
+		case "tags":
+			lxc.Tags = fmt.Sprintf("%v", v)
+		case "lxc":
+			// raw lxc.* keys are returned as [key, value] pairs and cannot be set through the API
+		default:
+			lxc.parseIndexedConfigKey(k, fmt.Sprintf("%v", v))
+		}
+	}
+	sortLxcNetInterfaces(lxc.Networks)
+	sortLxcMountPoints(lxc.MountPoints)
+	return lxc
+}
+
+// parseIndexedConfigKey stores netN and mpN entries in their typed slices.
+// Anything else, or an entry that fails to parse, is kept in Raw.
+func (lxc *LxcContainer) parseIndexedConfigKey(k, v string) {
+	if id, ok := parseIndexedKey(lxcNetKeyRegex, k); ok {
+		if n, err := ParseLxcNetInterface(id, v); err == nil {
+			lxc.Networks = append(lxc.Networks, *n)
+			return
+		}
+	}
+	if id, ok := parseIndexedKey(lxcMpKeyRegex, k); ok {
+		if mp, err := ParseLxcMountPoint(id, v); err == nil {
+			lxc.MountPoints = append(lxc.MountPoints, *mp)
+			return
+		}
+	}
+	lxc.Raw[k] = v
+}
+
+// parseLxcRootFs splits "local-lvm:vm-100-disk-0,size=8G" into its storage and size.
+func parseLxcRootFs(rootfs string) (storage, size string) {
+	volume, opts, _ := strings.Cut(rootfs, ",")
+	storage, _, _ = strings.Cut(volume, ":")
+	for _, opt := range strings.Split(opts, ",") {
+		if v, ok := strings.CutPrefix(opt, "size="); ok {
+			size = v
+		}
+	}
+	return storage, size
+}
+
+// toInt converts a numeric API value to int, returning 0 if it is not a number.
+func toInt(v any) int {
+	n, err := strconv.ParseFloat(toJSONNumber(v).String(), 64)
+	if err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+// ToConfigParams converts the updatable fields of an LxcContainer to API form
+// parameters for PUT /lxc/{vmid}/config. Create-only fields such as ostemplate,
+// storage and password are not included.
+func (lxc *LxcContainer) ToConfigParams() url.Values {
+	params := url.Values{}
+
+	if lxc.Hostname != "" {
+		params.Set("hostname", lxc.Hostname)
+	}
+	if lxc.Memory != 0 {
+		params.Set("memory", fmt.Sprintf("%d", lxc.Memory))
+	}
+	if lxc.Swap != 0 {
+		params.Set("swap", fmt.Sprintf("%d", lxc.Swap))
+	}
+	if lxc.Cores != 0 {
+		params.Set("cores", fmt.Sprintf("%d", lxc.Cores))
+	}
+	if lxc.CpuLimit != 0 {
+		params.Set("cpulimit", fmt.Sprintf("%d", lxc.CpuLimit))
+	}
+	if lxc.CpuUnits != 0 {
+		params.Set("cpuunits", fmt.Sprintf("%d", lxc.CpuUnits))
+	}
+	for _, n := range lxc.Networks {
+		params.Set(n.Key(), n.String())
+	}
+	for _, mp := range lxc.MountPoints {
+		params.Set(mp.Key(), mp.String())
+	}
+	if lxc.Nameserver != "" {
+		params.Set("nameserver", lxc.Nameserver)
+	}
+	if lxc.Searchdomain != "" {
+		params.Set("searchdomain", lxc.Searchdomain)
+	}
+	if lxc.Description != "" {
+		params.Set("description", lxc.Description)
+	}
+	if lxc.Arch != "" {
+		params.Set("arch", lxc.Arch)
+	}
+	if lxc.Cmode != "" {
+		params.Set("cmode", lxc.Cmode)
+	}
+	if lxc.Console != nil {
+		params.Set("console", formatPveBool(*lxc.Console))
+	}
+	if lxc.Debug != 0 {
+		params.Set("debug", fmt.Sprintf("%d", lxc.Debug))
+	}
+	if lxc.Features != nil {
+		params.Set("features", lxc.Features.String())
+	}
+	if lxc.Startup != "" {
+		params.Set("startup", lxc.Startup)
+	}
+	if lxc.Tags != "" {
+		params.Set("tags", lxc.Tags)
+	}
+
+	for k, v := range lxc.Raw {
+		if v != "" {
+			params.Set(k, v)
+		}
+	}
+	return params
+}
+
+func lxcPath(node string, vmid int, sub string) string {
+	return fmt.Sprintf("%s/%s/lxc/%d%s", apiNodesPath, url.PathEscape(node), vmid, sub)
+}
+
+// CreateLxc creates a new container on node and returns the create task.
+func (c *Client) CreateLxc(ctx context.Context, node string, lxc *LxcContainer) (*Task, error) {
+	if lxc == nil {
+		return nil, fmt.Errorf("LxcContainer cannot be nil")
+	}
+
+	form, err := lxc.ToFormParams()
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	for k, v := range form {
+		params.Set(k, v)
+	}
+
+	path := fmt.Sprintf("%s/%s/lxc", apiNodesPath, url.PathEscape(node))
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, path, params, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// ListLxcContainers returns the containers on node with their runtime status.
+func (c *Client) ListLxcContainers(ctx context.Context, node string) ([]LxcContainer, error) {
+	path := fmt.Sprintf("%s/%s/lxc", apiNodesPath, url.PathEscape(node))
+
+	var statuses []LxcStatus
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &statuses); err != nil {
+		return nil, err
+	}
+
+	containers := make([]LxcContainer, 0, len(statuses))
+	for i := range statuses {
+		status := statuses[i]
+		containers = append(containers, LxcContainer{
+			Node:     node,
+			VmId:     status.Vmid,
+			Hostname: status.Name,
+			Tags:     status.Tags,
+			Status:   &status,
+		})
+	}
+	return containers, nil
+}
+
+// GetLxcStatus returns the current runtime status of a container.
+func (c *Client) GetLxcStatus(ctx context.Context, node string, vmid int) (*LxcStatus, error) {
+	var status LxcStatus
+	if err := c.do(ctx, http.MethodGet, lxcPath(node, vmid, "/status/current"), nil, nil, false, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// lxcStatusAction posts to /lxc/{vmid}/status/{action} and returns the resulting task.
+func (c *Client) lxcStatusAction(ctx context.Context, node string, vmid int, action string, params url.Values) (*Task, error) {
+	path := lxcPath(node, vmid, "/status/"+action)
+
+	var upid string
+	slog.Info("Sending http client POST to "+action+" lxc", slog.String("node", node), slog.Int("vmid", vmid), slog.String("path", path))
+	if err := c.doForm(ctx, http.MethodPost, path, params, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// StartLxc starts a container.
+func (c *Client) StartLxc(ctx context.Context, node string, vmid int) (*Task, error) {
+	return c.lxcStatusAction(ctx, node, vmid, "start", url.Values{})
+}
+
+// StopLxc stops a container immediately, without a clean shutdown.
+func (c *Client) StopLxc(ctx context.Context, node string, vmid int) (*Task, error) {
+	return c.lxcStatusAction(ctx, node, vmid, "stop", url.Values{})
+}
+
+// ShutdownLxc cleanly shuts down a container. timeout is in seconds (0 uses the
+// PVE default); if forceStop is set the container is stopped once it expires.
+func (c *Client) ShutdownLxc(ctx context.Context, node string, vmid int, timeout int, forceStop bool) (*Task, error) {
+	params := url.Values{}
+	if timeout > 0 {
+		params.Set("timeout", fmt.Sprintf("%d", timeout))
+	}
+	if forceStop {
+		params.Set("forceStop", "1")
+	}
+	return c.lxcStatusAction(ctx, node, vmid, "shutdown", params)
+}
+
+// RebootLxc reboots a container by shutting it down and starting it again.
+func (c *Client) RebootLxc(ctx context.Context, node string, vmid int) (*Task, error) {
+	return c.lxcStatusAction(ctx, node, vmid, "reboot", url.Values{})
+}
+
+// SuspendLxc freezes a running container.
+func (c *Client) SuspendLxc(ctx context.Context, node string, vmid int) (*Task, error) {
+	return c.lxcStatusAction(ctx, node, vmid, "suspend", url.Values{})
+}
+
+// ResumeLxc resumes a suspended container.
+func (c *Client) ResumeLxc(ctx context.Context, node string, vmid int) (*Task, error) {
+	return c.lxcStatusAction(ctx, node, vmid, "resume", url.Values{})
+}
+
+// GetLxcConfig returns a typed container config.
+func (c *Client) GetLxcConfig(ctx context.Context, node string, vmid int) (*LxcContainer, error) {
+	var raw map[string]any
+	if err := c.do(ctx, http.MethodGet, lxcPath(node, vmid, "/config"), nil, nil, false, &raw); err != nil {
+		return nil, err
+	}
+
+	lxc := ParseLxcContainerConfig(raw)
+	lxc.Node = node
+	lxc.VmId = vmid
+	return lxc, nil
+}
+
+// UpdateLxcConfig updates a container configuration using LxcContainer.
+func (c *Client) UpdateLxcConfig(ctx context.Context, node string, vmid int, lxc *LxcContainer) error {
+	if lxc == nil {
+		return fmt.Errorf("LxcContainer cannot be nil")
+	}
+	return c.doForm(ctx, http.MethodPut, lxcPath(node, vmid, "/config"), lxc.ToConfigParams(), nil)
+}
+
+// ResizeLxcDisk resizes the rootfs or an mpN mount point. size is either an
+// absolute size ("20G") or an increment prefixed with '+' ("+5G").
+func (c *Client) ResizeLxcDisk(ctx context.Context, node string, vmid int, disk, size string) (*Task, error) {
+	if disk == "" || size == "" {
+		return nil, fmt.Errorf("disk and size are required")
+	}
+
+	params := url.Values{}
+	params.Set("disk", disk)
+	params.Set("size", size)
+
+	var upid string
+	if err := c.doForm(ctx, http.MethodPut, lxcPath(node, vmid, "/resize"), params, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
@@ -0,0 +1,301 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PrunePolicy is a typed prune-backups retention setting such as
+// "keep-last=3,keep-daily=7,keep-weekly=4".
+type PrunePolicy struct {
+	KeepAll     bool `json:"keepAll,omitempty"`
+	KeepLast    int  `json:"keepLast,omitempty"`
+	KeepHourly  int  `json:"keepHourly,omitempty"`
+	KeepDaily   int  `json:"keepDaily,omitempty"`
+	KeepWeekly  int  `json:"keepWeekly,omitempty"`
+	KeepMonthly int  `json:"keepMonthly,omitempty"`
+	KeepYearly  int  `json:"keepYearly,omitempty"`
+}
+
+// ParsePrunePolicy parses a prune-backups property string.
+func ParsePrunePolicy(s string) (*PrunePolicy, error) {
+	props, err := parsePropertyString(s, "")
+	if err != nil {
+		return nil, fmt.Errorf("parsing prune-backups: %w", err)
+	}
+
+	p := &PrunePolicy{}
+	keepAll, err := props.takeBool("keep-all")
+	if err != nil {
+		return nil, fmt.Errorf("parsing prune-backups: %w", err)
+	}
+	p.KeepAll = keepAll != nil && *keepAll
+	for key, dst := range p.counts() {
+		if *dst, err = props.takeInt(key); err != nil {
+			return nil, fmt.Errorf("parsing prune-backups: %w", err)
+		}
+		if *dst < 0 {
+			return nil, fmt.Errorf("parsing prune-backups: %s must not be negative", key)
+		}
+	}
+	if len(props) > 0 {
+		return nil, fmt.Errorf("parsing prune-backups: unknown option(s) %v", props.remaining())
+	}
+	if p.mixesKeepAll() {
+		return nil, fmt.Errorf("parsing prune-backups: keep-all cannot be combined with other keep options")
+	}
+	return p, nil
+}
+
+// mixesKeepAll reports whether keep-all is set together with another keep
+// option, which PVE rejects.
+func (p *PrunePolicy) mixesKeepAll() bool {
+	return p.KeepAll && *p != (PrunePolicy{KeepAll: true})
+}
+
+func (p *PrunePolicy) validate(verr *ValidationError) {
+	if p.mixesKeepAll() {
+		verr.Add("prune-backups", "keep-all cannot be combined with other keep options")
+	}
+	for key, n := range p.counts() {
+		if *n < 0 {
+			verr.Add("prune-backups", "%s must not be negative", key)
+		}
+	}
+}
+
+func (p *PrunePolicy) counts() map[string]*int {
+	return map[string]*int{
+		"keep-last":    &p.KeepLast,
+		"keep-hourly":  &p.KeepHourly,
+		"keep-daily":   &p.KeepDaily,
+		"keep-weekly":  &p.KeepWeekly,
+		"keep-monthly": &p.KeepMonthly,
+		"keep-yearly":  &p.KeepYearly,
+	}
+}
+
+// String formats the policy as a PVE property string.
+func (p *PrunePolicy) String() string {
+	var b propertyStringBuilder
+	if p.KeepAll {
+		b.str("keep-all", "1")
+	}
+	b.int("keep-last", p.KeepLast)
+	b.int("keep-hourly", p.KeepHourly)
+	b.int("keep-daily", p.KeepDaily)
+	b.int("keep-weekly", p.KeepWeekly)
+	b.int("keep-monthly", p.KeepMonthly)
+	b.int("keep-yearly", p.KeepYearly)
+	return b.String()
+}
+
+// keepsEverything reports whether the policy retains every backup, which is
+// the case for keep-all and for a policy without any keep option.
+func (p *PrunePolicy) keepsEverything() bool {
+	return p.KeepAll || *p == PrunePolicy{}
+}
+
+// PruneMark values.
+const (
+	PruneKeep      = "keep"
+	PruneRemove    = "remove"
+	PruneProtected = "protected"
+	PruneRenamed   = "renamed"
+)
+
+// PruneMark is the prune decision for a single backup volume.
+type PruneMark struct {
+	Volid string `json:"volid"`
+	Type  string `json:"type"` // "qemu", "lxc" or "unknown"
+	VmId  int    `json:"vmid,omitempty"`
+	CTime int64  `json:"ctime"`
+	Mark  string `json:"mark"` // PruneKeep, PruneRemove, PruneProtected or PruneRenamed
+}
+
+// PruneFilter limits pruning to one guest type and/or VMID. Zero values match everything.
+type PruneFilter struct {
+	Type string // "qemu" or "lxc"
+	VmId int
+}
+
+func prunePath(node, storage string, policy *PrunePolicy, filter PruneFilter) string {
+	query := url.Values{}
+	if policy != nil {
+		query.Set("prune-backups", policy.String())
+	}
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
+	}
+	if filter.VmId != 0 {
+		query.Set("vmid", fmt.Sprintf("%d", filter.VmId))
+	}
+	path := fmt.Sprintf("%s/%s/storage/%s/prunebackups", apiNodesPath, url.PathEscape(node), url.PathEscape(storage))
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path
+}
+
+// PruneBackupsDryRun asks PVE which backups on storage policy would keep or
+// remove. A nil policy uses the storage's configured retention.
+func (c *Client) PruneBackupsDryRun(ctx context.Context, node, storage string, policy *PrunePolicy, filter PruneFilter) ([]PruneMark, error) {
+	var marks []PruneMark
+	if err := c.do(ctx, http.MethodGet, prunePath(node, storage, policy, filter), nil, nil, false, &marks); err != nil {
+		return nil, err
+	}
+	return marks, nil
+}
+
+// PruneBackups removes the backups on storage that policy does not keep. A nil
+// policy uses the storage's configured retention.
+func (c *Client) PruneBackups(ctx context.Context, node, storage string, policy *PrunePolicy, filter PruneFilter) (*Task, error) {
+	var upid string
+	if err := c.do(ctx, http.MethodDelete, prunePath(node, storage, policy, filter), nil, nil, true, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// backupGuestType derives the guest type of a backup from its volid.
+func backupGuestType(volid string) string {
+	switch {
+	case strings.Contains(volid, "vzdump-qemu-"), strings.Contains(volid, "backup/vm/"):
+		return "qemu"
+	case strings.Contains(volid, "vzdump-lxc-"), strings.Contains(volid, "vzdump-openvz-"), strings.Contains(volid, "backup/ct/"):
+		return "lxc"
+	}
+	return "unknown"
+}
+
+// SimulatePrune marks backups locally the way PVE's prunebackups would under
+// policy, without contacting the cluster. Backups are grouped per guest and
+// type; time buckets are computed in loc (the node's time zone, or time.Local
+// if nil). Volumes that are not backups are ignored.
+func SimulatePrune(backups []StorageVolume, policy PrunePolicy, loc *time.Location) []PruneMark {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	groups := make(map[string][]*PruneMark)
+	var order []string
+	marks := make([]*PruneMark, 0, len(backups))
+	protected := make(map[*PruneMark]bool)
+	for _, v := range backups {
+		if v.Content != "" && v.Content != Backup {
+			continue
+		}
+		m := &PruneMark{Volid: v.Volid, Type: backupGuestType(v.Volid), VmId: v.VmId, CTime: v.CTime}
+		if m.Type == "unknown" {
+			// PVE leaves backups it cannot attribute to a guest alone.
+			m.Mark = PruneRenamed
+		}
+		protected[m] = bool(v.Protected)
+		marks = append(marks, m)
+		if m.Mark != "" {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%d", m.Type, m.VmId)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], m)
+	}
+
+	for _, key := range order {
+		markBackupGroup(groups[key], policy, protected, loc)
+	}
+
+	result := make([]PruneMark, len(marks))
+	for i, m := range marks {
+		result[i] = *m
+	}
+	return result
+}
+
+// markBackupGroup applies policy to the backups of a single guest, mirroring
+// PVE::Storage::prune_mark_backup_group.
+func markBackupGroup(group []*PruneMark, policy PrunePolicy, protected map[*PruneMark]bool, loc *time.Location) {
+	sort.SliceStable(group, func(i, j int) bool { return group[i].CTime > group[j].CTime })
+
+	candidates := make([]*PruneMark, 0, len(group))
+	for _, m := range group {
+		if protected[m] {
+			m.Mark = PruneProtected
+			continue
+		}
+		candidates = append(candidates, m)
+	}
+
+	if policy.keepsEverything() {
+		for _, m := range candidates {
+			m.Mark = PruneKeep
+		}
+		return
+	}
+
+	bucket := func(layout func(t time.Time) string) func(int64) string {
+		return func(ctime int64) string { return layout(time.Unix(ctime, 0).In(loc)) }
+	}
+	rules := []struct {
+		count int
+		id    func(int64) string
+	}{
+		{policy.KeepLast, func(ctime int64) string { return fmt.Sprintf("%d", ctime) }},
+		{policy.KeepHourly, bucket(func(t time.Time) string { return t.Format("2006/01/02/15") })},
+		{policy.KeepDaily, bucket(func(t time.Time) string { return t.Format("2006/01/02") })},
+		{policy.KeepWeekly, bucket(func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d/%02d", year, week)
+		})},
+		{policy.KeepMonthly, bucket(func(t time.Time) string { return t.Format("2006/01") })},
+		{policy.KeepYearly, bucket(func(t time.Time) string { return t.Format("2006") })},
+	}
+	for _, rule := range rules {
+		markFirst(candidates, rule.count, rule.id)
+	}
+
+	for _, m := range candidates {
+		if m.Mark == "" {
+			m.Mark = PruneRemove
+		}
+	}
+}
+
+// markFirst keeps the newest backup of each of the first count time buckets
+// that do not already contain a kept backup.
+func markFirst(candidates []*PruneMark, count int, id func(int64) string) {
+	if count == 0 {
+		return
+	}
+
+	alreadyIncluded := make(map[string]bool)
+	for _, m := range candidates {
+		if m.Mark == PruneKeep {
+			alreadyIncluded[id(m.CTime)] = true
+		}
+	}
+
+	newlyIncluded := make(map[string]bool)
+	for _, m := range candidates {
+		bucket := id(m.CTime)
+		if m.Mark != "" || alreadyIncluded[bucket] {
+			continue
+		}
+		if !newlyIncluded[bucket] {
+			if len(newlyIncluded) >= count {
+				break
+			}
+			newlyIncluded[bucket] = true
+			m.Mark = PruneKeep
+		} else {
+			m.Mark = PruneRemove
+		}
+	}
+}
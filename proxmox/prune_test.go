@@ -0,0 +1,197 @@
+package proxmox
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSimulatePrune(t *testing.T) {
+	type backup struct {
+		vmid      int
+		at        string // RFC 3339
+		protected bool
+	}
+	tests := []struct {
+		name    string
+		policy  PrunePolicy
+		backups []backup
+		want    []string // marks in input order
+	}{
+		{
+			name:   "keep-last",
+			policy: PrunePolicy{KeepLast: 2},
+			backups: []backup{
+				{vmid: 100, at: "2025-01-01T01:00:00Z"},
+				{vmid: 100, at: "2025-01-02T01:00:00Z"},
+				{vmid: 100, at: "2025-01-03T01:00:00Z"},
+			},
+			want: []string{PruneRemove, PruneKeep, PruneKeep},
+		},
+		{
+			name:   "keep-hourly",
+			policy: PrunePolicy{KeepHourly: 2},
+			backups: []backup{
+				{vmid: 100, at: "2025-01-01T10:00:00Z"},
+				{vmid: 100, at: "2025-01-01T10:30:00Z"},
+				{vmid: 100, at: "2025-01-01T11:15:00Z"},
+				{vmid: 100, at: "2025-01-01T12:05:00Z"},
+			},
+			want: []string{PruneRemove, PruneRemove, PruneKeep, PruneKeep},
+		},
+		{
+			name:   "keep-daily keeps the newest backup of each day",
+			policy: PrunePolicy{KeepDaily: 2},
+			backups: []backup{
+				{vmid: 100, at: "2025-01-01T08:00:00Z"},
+				{vmid: 100, at: "2025-01-02T08:00:00Z"},
+				{vmid: 100, at: "2025-01-02T20:00:00Z"},
+				{vmid: 100, at: "2025-01-03T09:00:00Z"},
+			},
+			want: []string{PruneRemove, PruneRemove, PruneKeep, PruneKeep},
+		},
+		{
+			name:   "keep-weekly uses ISO weeks",
+			policy: PrunePolicy{KeepWeekly: 2},
+			backups: []backup{
+				{vmid: 100, at: "2025-01-06T01:00:00Z"}, // Monday, week 2
+				{vmid: 100, at: "2025-01-12T01:00:00Z"}, // Sunday, week 2
+				{vmid: 100, at: "2025-01-13T01:00:00Z"}, // Monday, week 3
+			},
+			want: []string{PruneRemove, PruneKeep, PruneKeep},
+		},
+		{
+			name:   "keep-monthly",
+			policy: PrunePolicy{KeepMonthly: 1},
+			backups: []backup{
+				{vmid: 100, at: "2025-01-05T01:00:00Z"},
+				{vmid: 100, at: "2025-02-03T01:00:00Z"},
+				{vmid: 100, at: "2025-02-20T01:00:00Z"},
+			},
+			want: []string{PruneRemove, PruneRemove, PruneKeep},
+		},
+		{
+			name:   "keep-yearly",
+			policy: PrunePolicy{KeepYearly: 2},
+			backups: []backup{
+				{vmid: 100, at: "2023-06-01T01:00:00Z"},
+				{vmid: 100, at: "2024-03-01T01:00:00Z"},
+				{vmid: 100, at: "2024-09-01T01:00:00Z"},
+			},
+			want: []string{PruneKeep, PruneRemove, PruneKeep},
+		},
+		{
+			name:   "rules skip buckets already kept by earlier rules",
+			policy: PrunePolicy{KeepLast: 1, KeepDaily: 2},
+			backups: []backup{
+				{vmid: 100, at: "2025-01-01T09:00:00Z"},
+				{vmid: 100, at: "2025-01-02T10:00:00Z"},
+				{vmid: 100, at: "2025-01-03T08:00:00Z"},
+				{vmid: 100, at: "2025-01-03T09:00:00Z"},
+			},
+			want: []string{PruneKeep, PruneKeep, PruneRemove, PruneKeep},
+		},
+		{
+			name:   "protected backups do not count towards the policy",
+			policy: PrunePolicy{KeepLast: 1},
+			backups: []backup{
+				{vmid: 100, at: "2025-01-01T01:00:00Z"},
+				{vmid: 100, at: "2025-01-02T01:00:00Z"},
+				{vmid: 100, at: "2025-01-03T01:00:00Z", protected: true},
+			},
+			want: []string{PruneRemove, PruneKeep, PruneProtected},
+		},
+		{
+			name:   "keep-all still reports protected backups",
+			policy: PrunePolicy{KeepAll: true},
+			backups: []backup{
+				{vmid: 100, at: "2025-01-01T01:00:00Z", protected: true},
+				{vmid: 100, at: "2025-01-02T01:00:00Z"},
+			},
+			want: []string{PruneProtected, PruneKeep},
+		},
+		{
+			name:   "empty policy keeps everything",
+			policy: PrunePolicy{},
+			backups: []backup{
+				{vmid: 100, at: "2025-01-01T01:00:00Z"},
+				{vmid: 100, at: "2025-01-02T01:00:00Z"},
+			},
+			want: []string{PruneKeep, PruneKeep},
+		},
+		{
+			name:   "guests are pruned separately",
+			policy: PrunePolicy{KeepLast: 1},
+			backups: []backup{
+				{vmid: 100, at: "2025-01-01T01:00:00Z"},
+				{vmid: 101, at: "2025-01-02T01:00:00Z"},
+				{vmid: 100, at: "2025-01-03T01:00:00Z"},
+			},
+			want: []string{PruneRemove, PruneKeep, PruneKeep},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			volumes := make([]StorageVolume, len(tt.backups))
+			for i, b := range tt.backups {
+				at, err := time.Parse(time.RFC3339, b.at)
+				if err != nil {
+					t.Fatal(err)
+				}
+				volumes[i] = StorageVolume{
+					Volid:     fmt.Sprintf("local:backup/vzdump-qemu-%d-%s.vma.zst", b.vmid, at.Format("2006_01_02-15_04_05")),
+					Content:   Backup,
+					CTime:     at.Unix(),
+					VmId:      b.vmid,
+					Protected: PveBool(b.protected),
+				}
+			}
+
+			marks := SimulatePrune(volumes, tt.policy, time.UTC)
+			if len(marks) != len(tt.want) {
+				t.Fatalf("got %d marks, want %d", len(marks), len(tt.want))
+			}
+			for i, m := range marks {
+				if m.Mark != tt.want[i] {
+					t.Errorf("%s: mark = %q, want %q", m.Volid, m.Mark, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSimulatePruneSkipsUnknownBackups(t *testing.T) {
+	marks := SimulatePrune([]StorageVolume{
+		{Volid: "local:backup/custom.tar", Content: Backup, CTime: 1},
+		{Volid: "local:iso/debian.iso", Content: "iso"},
+	}, PrunePolicy{KeepLast: 1}, time.UTC)
+	if len(marks) != 1 || marks[0].Mark != PruneRenamed {
+		t.Errorf("marks = %+v, want a single %q mark", marks, PruneRenamed)
+	}
+}
+
+func TestParsePrunePolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    PrunePolicy
+		wantErr bool
+	}{
+		{in: "keep-last=3,keep-daily=7", want: PrunePolicy{KeepLast: 3, KeepDaily: 7}},
+		{in: "keep-all=1", want: PrunePolicy{KeepAll: true}},
+		{in: "keep-all=0,keep-last=2", want: PrunePolicy{KeepLast: 2}},
+		{in: "keep-all=1,keep-last=2", wantErr: true},
+		{in: "keep-last=-1", wantErr: true},
+		{in: "keep-often=1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePrunePolicy(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePrunePolicy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && *got != tt.want {
+			t.Errorf("ParsePrunePolicy(%q) = %+v, want %+v", tt.in, *got, tt.want)
+		}
+	}
+}
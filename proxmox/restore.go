@@ -0,0 +1,261 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// RestoreOptions are the parameters for restoring a guest from a backup archive.
+type RestoreOptions struct {
+	Archive string // Required: backup volid, e.g. "pbs:backup/ct/101/2024-05-01T02:00:00Z"
+	VmId    int    // Required: target VMID
+	Storage string // Default target storage for restored disks
+	// StorageMap moves individual container volumes ("rootfs", "mp0", ...) to
+	// another storage. Volume sizes and options are taken from the archive.
+	// Containers only.
+	StorageMap  map[string]string
+	Unique      bool // Regenerate MAC addresses and other unique properties
+	Force       bool // Overwrite an existing guest with the same VMID
+	LiveRestore bool // Start the VM while the restore runs in the background (VMs from PBS only)
+	BwLimit     int  // KiB/s
+	Pool        string
+	Start       bool // Start the guest after a successful restore
+}
+
+func (o *RestoreOptions) validate(lxc bool) error {
+	verr := &ValidationError{Resource: "RestoreOptions"}
+	if o.Archive == "" {
+		verr.Add("archive", "required")
+	}
+	if o.VmId <= 0 {
+		verr.Add("vmid", "must be a positive integer, got %d", o.VmId)
+	}
+	if lxc && o.LiveRestore {
+		verr.Add("live-restore", "only supported for VMs")
+	}
+	if !lxc && len(o.StorageMap) > 0 {
+		verr.Add("storage", "per-volume storage mapping is only supported for containers; use Storage")
+	}
+	if o.LiveRestore && o.Start {
+		verr.Add("start", "cannot be combined with live-restore, which always starts the VM")
+	}
+	return verr.Err()
+}
+
+func (o *RestoreOptions) encode(params url.Values) {
+	params.Set("vmid", fmt.Sprintf("%d", o.VmId))
+	if o.Storage != "" {
+		params.Set("storage", o.Storage)
+	}
+	if o.Unique {
+		params.Set("unique", "1")
+	}
+	if o.Force {
+		params.Set("force", "1")
+	}
+	if o.LiveRestore {
+		params.Set("live-restore", "1")
+	}
+	if o.BwLimit != 0 {
+		params.Set("bwlimit", fmt.Sprintf("%d", o.BwLimit))
+	}
+	if o.Pool != "" {
+		params.Set("pool", o.Pool)
+	}
+	if o.Start {
+		params.Set("start", "1")
+	}
+}
+
+// RestoreVM restores a VM from a vzdump or PBS archive.
+func (c *Client) RestoreVM(ctx context.Context, node string, opts RestoreOptions) (*Task, error) {
+	if err := opts.validate(false); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("archive", opts.Archive)
+	opts.encode(params)
+
+	path := fmt.Sprintf("%s/%s/qemu", apiNodesPath, url.PathEscape(node))
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, path, params, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// RestoreLXC restores a container from a vzdump or PBS archive.
+func (c *Client) RestoreLXC(ctx context.Context, node string, opts RestoreOptions) (*Task, error) {
+	if err := opts.validate(true); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("ostemplate", opts.Archive)
+	params.Set("restore", "1")
+	opts.encode(params)
+
+	if len(opts.StorageMap) > 0 {
+		cfg, err := c.ExtractBackupConfig(ctx, node, opts.Archive)
+		if err != nil {
+			return nil, err
+		}
+		overrides, err := lxcRestoreVolumeOverrides(ParseGuestConfigText(cfg), opts.StorageMap)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range overrides {
+			params.Set(k, v)
+		}
+	}
+
+	path := fmt.Sprintf("%s/%s/lxc", apiNodesPath, url.PathEscape(node))
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, path, params, &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// lxcRestoreVolumeOverrides builds "storage:sizeGiB" rootfs/mpN overrides for
+// the volumes in storageMap, keeping the size and options from the archived config.
+func lxcRestoreVolumeOverrides(cfg map[string]string, storageMap map[string]string) (map[string]string, error) {
+	overrides := make(map[string]string, len(storageMap))
+	for key, storage := range storageMap {
+		value, ok := cfg[key]
+		if !ok {
+			return nil, fmt.Errorf("volume %s not found in backup config", key)
+		}
+
+		id := -1
+		if key != "rootfs" {
+			n, ok := parseIndexedKey(lxcMpKeyRegex, key)
+			if !ok {
+				return nil, fmt.Errorf("cannot map %s: only rootfs and mpN volumes can be mapped", key)
+			}
+			id = n
+		}
+
+		mp, err := ParseLxcMountPoint(id, value)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(mp.Volume, "/") {
+			return nil, fmt.Errorf("cannot map %s: bind mounts are not restored to storage", key)
+		}
+		size, err := sizeToGiB(mp.Size)
+		if err != nil {
+			return nil, fmt.Errorf("volume %s: %w", key, err)
+		}
+		mp.Volume = storage + ":" + size
+		mp.Size = ""
+		overrides[key] = mp.String()
+	}
+	return overrides, nil
+}
+
+// sizeToGiB converts a PVE disk size such as "8G" or "512M" to GiB, the unit
+// PVE expects when allocating a new volume.
+func sizeToGiB(size string) (string, error) {
+	if size == "" {
+		return "", fmt.Errorf("size unknown")
+	}
+	unit := size[len(size)-1]
+	num := size
+	factor := 1.0 / (1 << 30) // bytes
+	switch unit {
+	case 'K', 'k':
+		factor = 1.0 / (1 << 20)
+	case 'M', 'm':
+		factor = 1.0 / (1 << 10)
+	case 'G', 'g':
+		factor = 1
+	case 'T', 't':
+		factor = 1 << 10
+	default:
+		if unit < '0' || unit > '9' {
+			return "", fmt.Errorf("invalid size %q: unknown unit %q", size, unit)
+		}
+	}
+	if unit < '0' || unit > '9' {
+		num = size[:len(size)-1]
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid size %q: %w", size, err)
+	}
+	return strconv.FormatFloat(n*factor, 'f', -1, 64), nil
+}
+
+// ExtractBackupConfig returns the guest config embedded in a backup archive.
+func (c *Client) ExtractBackupConfig(ctx context.Context, node, volid string) (string, error) {
+	path := fmt.Sprintf("%s/%s/vzdump/extractconfig?volume=%s", apiNodesPath, url.PathEscape(node), url.QueryEscape(volid))
+
+	var cfg string
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &cfg); err != nil {
+		return "", err
+	}
+	return cfg, nil
+}
+
+// ParseGuestConfigText parses a guest config file ("key: value" lines) into a
+// map. Comment lines are joined into "description"; snapshot and pending
+// sections are ignored.
+func ParseGuestConfigText(text string) map[string]string {
+	cfg := make(map[string]string)
+	var description []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "[") {
+			break
+		}
+		if comment, ok := strings.CutPrefix(line, "#"); ok {
+			description = append(description, comment)
+			continue
+		}
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		cfg[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	if len(description) > 0 {
+		if _, set := cfg["description"]; !set {
+			cfg["description"] = strings.Join(description, "\n")
+		}
+	}
+	return cfg
+}
+
+func configTextToRaw(cfg map[string]string) map[string]any {
+	raw := make(map[string]any, len(cfg))
+	for k, v := range cfg {
+		raw[k] = v
+	}
+	return raw
+}
+
+// PreviewVMRestore returns the VM config a restore of volid would create.
+func (c *Client) PreviewVMRestore(ctx context.Context, node, volid string) (*ProxmoxQemuVmConfig, error) {
+	text, err := c.ExtractBackupConfig(ctx, node, volid)
+	if err != nil {
+		return nil, err
+	}
+	return ParseQemuVmConfig(configTextToRaw(ParseGuestConfigText(text))), nil
+}
+
+// PreviewLxcRestore returns the container config a restore of volid would create.
+func (c *Client) PreviewLxcRestore(ctx context.Context, node, volid string) (*LxcContainer, error) {
+	text, err := c.ExtractBackupConfig(ctx, node, volid)
+	if err != nil {
+		return nil, err
+	}
+	lxc := ParseLxcContainerConfig(configTextToRaw(ParseGuestConfigText(text)))
+	lxc.Node = node
+	return lxc, nil
+}
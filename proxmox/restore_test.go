@@ -0,0 +1,30 @@
+package proxmox
+
+import "testing"
+
+func TestSizeToGiB(t *testing.T) {
+	tests := []struct {
+		size, want string
+		wantErr    bool
+	}{
+		{size: "8G", want: "8"},
+		{size: "512M", want: "0.5"},
+		{size: "1048576K", want: "1"},
+		{size: "2T", want: "2048"},
+		{size: "1073741824", want: "1"},
+		{size: "8P", wantErr: true},
+		{size: "8X", wantErr: true},
+		{size: "", wantErr: true},
+		{size: "G", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := sizeToGiB(tt.size)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("sizeToGiB(%q) error = %v, wantErr %v", tt.size, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("sizeToGiB(%q) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package proxmox
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// snapshotNameRegex matches the pve-configid format PVE requires for snapshot names.
+var snapshotNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_\-]+$`)
+
+// maxSnapshotNameLen is the longest snapshot name PVE accepts.
+const maxSnapshotNameLen = 40
+
+// validateSnapshotName records an error on field if name is not a valid
+// snapshot name.
+func validateSnapshotName(verr *ValidationError, field, name string) {
+	if !snapshotNameRegex.MatchString(name) {
+		verr.Add(field, "must start with a letter and contain only letters, digits, '-' and '_', got %q", name)
+	} else if len(name) > maxSnapshotNameLen {
+		verr.Add(field, "must be at most %d characters, got %d", maxSnapshotNameLen, len(name))
+	}
+}
+
+// Snapshot is an entry of a guest's snapshot list. The list always contains a
+// pseudo snapshot named "current" that represents the running state.
+type Snapshot struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parent      string `json:"parent,omitempty"`
+	SnapTime    int64  `json:"snaptime,omitempty"`
+	VmState     int    `json:"vmstate,omitempty"` // QEMU only: RAM state was saved
+}
+
+// IsCurrent reports whether s is the "current" pseudo snapshot.
+func (s *Snapshot) IsCurrent() bool {
+	return s.Name == "current"
+}
+
+// SnapshotOptions are the parameters for creating a guest snapshot.
+type SnapshotOptions struct {
+	Name        string // Required
+	Description string
+	VmState     bool // QEMU only: include RAM state
+}
+
+func (o *SnapshotOptions) validate() error {
+	verr := &ValidationError{Resource: "SnapshotOptions"}
+	validateSnapshotName(verr, "snapname", o.Name)
+	return verr.Err()
+}
+
+// toParams encodes the options. vmState is only sent for guests that support it.
+func (o *SnapshotOptions) toParams(vmState bool) url.Values {
+	params := url.Values{}
+	params.Set("snapname", o.Name)
+	if o.Description != "" {
+		params.Set("description", o.Description)
+	}
+	if vmState && o.VmState {
+		params.Set("vmstate", "1")
+	}
+	return params
+}
+
+// CloneOptions are the parameters for cloning a guest or template.
+type CloneOptions struct {
+	NewID       int    // Required: VMID of the clone
+	Name        string // Hostname for containers, name for VMs
+	Description string
+	Full        bool   // Full copy instead of a linked clone; linked clones require a template source
+	Storage     string // Target storage for full clones
+	Target      string // Target node; the source must be on shared storage when it differs
+	Pool        string
+	SnapName    string // Clone from this snapshot instead of the current state
+	BwLimit     int    // KiB/s
+}
+
+func (o *CloneOptions) validate() error {
+	verr := &ValidationError{Resource: "CloneOptions"}
+	if o.NewID <= 0 {
+		verr.Add("newid", "must be a positive integer, got %d", o.NewID)
+	}
+	if o.Storage != "" && !o.Full {
+		verr.Add("storage", "target storage is only allowed for full clones")
+	}
+	if o.SnapName != "" {
+		validateSnapshotName(verr, "snapname", o.SnapName)
+	}
+	return verr.Err()
+}
+
+// toParams encodes the options. nameKey is "hostname" for containers and "name" for VMs.
+func (o *CloneOptions) toParams(nameKey string) url.Values {
+	params := url.Values{}
+	params.Set("newid", fmt.Sprintf("%d", o.NewID))
+	if o.Name != "" {
+		params.Set(nameKey, o.Name)
+	}
+	if o.Description != "" {
+		params.Set("description", o.Description)
+	}
+	if o.Full {
+		params.Set("full", "1")
+	}
+	if o.Storage != "" {
+		params.Set("storage", o.Storage)
+	}
+	if o.Target != "" {
+		params.Set("target", o.Target)
+	}
+	if o.Pool != "" {
+		params.Set("pool", o.Pool)
+	}
+	if o.SnapName != "" {
+		params.Set("snapname", o.SnapName)
+	}
+	if o.BwLimit != 0 {
+		params.Set("bwlimit", fmt.Sprintf("%d", o.BwLimit))
+	}
+	return params
+}
@@ -0,0 +1,63 @@
+package proxmox
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "pre-upgrade"},
+		{name: "snap_2025_01"},
+		{name: "A" + strings.Repeat("b", maxSnapshotNameLen-1)},
+		{name: "", wantErr: true},
+		{name: "s", wantErr: true},
+		{name: "1st", wantErr: true},
+		{name: "before upgrade", wantErr: true},
+		{name: "a.b", wantErr: true},
+		{name: "A" + strings.Repeat("b", maxSnapshotNameLen), wantErr: true},
+	}
+	for _, tt := range tests {
+		err := (&SnapshotOptions{Name: tt.name}).validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validate(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		var verr *ValidationError
+		if err != nil && (!errors.As(err, &verr) || verr.Fields[0].Field != "snapname") {
+			t.Errorf("validate(%q) error = %v, want a snapname ValidationError", tt.name, err)
+		}
+	}
+}
+
+func TestCloneOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   CloneOptions
+		fields []string
+	}{
+		{name: "linked", opts: CloneOptions{NewID: 200}},
+		{name: "full to storage", opts: CloneOptions{NewID: 200, Full: true, Storage: "local-lvm", SnapName: "base"}},
+		{name: "missing id", opts: CloneOptions{}, fields: []string{"newid"}},
+		{name: "storage on linked clone", opts: CloneOptions{NewID: 200, Storage: "local-lvm"}, fields: []string{"storage"}},
+		{name: "invalid snapshot", opts: CloneOptions{NewID: 200, SnapName: "1st"}, fields: []string{"snapname"}},
+	}
+	for _, tt := range tests {
+		err := tt.opts.validate()
+		var got []string
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			for _, f := range verr.Fields {
+				got = append(got, f.Field)
+			}
+		} else if err != nil {
+			t.Errorf("%s: error = %v, want *ValidationError", tt.name, err)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+			t.Errorf("%s: invalid fields = %v, want %v", tt.name, got, tt.fields)
+		}
+	}
+}
@@ -1,5 +1,17 @@
 package proxmox
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const apiStoragePath string = "/api2/json/storage"
+
 type ProxmoxStorageType string
 type ProxmoxStorageContentType string
 type ProxmoxStorageEnabledContent map[ProxmoxStorageContentType]bool
@@ -8,38 +20,307 @@ type ProxmoxResource interface {
 	ProxmoxQemuVmConfig | LxcContainer
 }
 
+// Storage type values match the "type" field returned by PVE.
+//
+// Breaking change: Directory, LvmThin and ZfsOverIscsi used to be "directory",
+// "lvm-thin" and "zfs-iscsi", which PVE does not know. ZfsOverIscsi is now
+// "zfs", the same value as the deprecated ZFS, so a switch listing both no
+// longer compiles, and comparisons with ZfsOverIscsi now match PVE's "zfs"
+// pools. ParseProxmoxStorageType still accepts the old names.
 const (
-	Directory           ProxmoxStorageType = "directory"
+	Directory           ProxmoxStorageType = "dir"
 	LVM                 ProxmoxStorageType = "lvm"
-	LvmThin             ProxmoxStorageType = "lvm-thin"
+	LvmThin             ProxmoxStorageType = "lvmthin"
 	BTRFS               ProxmoxStorageType = "btrfs"
 	NFS                 ProxmoxStorageType = "nfs"
 	SmbCifs             ProxmoxStorageType = "cifs"
 	GlusterFs           ProxmoxStorageType = "glusterfs"
 	CephFs              ProxmoxStorageType = "cephfs"
 	RBD                 ProxmoxStorageType = "rbd"
-	ZfsOverIscsi        ProxmoxStorageType = "zfs-iscsi"
-	ZFS                 ProxmoxStorageType = "zfs"
+	ZfsOverIscsi        ProxmoxStorageType = "zfs"
+	ZfsPool             ProxmoxStorageType = "zfspool"
 	ProxmoxBackupServer ProxmoxStorageType = "pbs"
+
+	// Deprecated: ZFS is PVE's "zfs" type, which is ZFS over iSCSI. Use
+	// ZfsPool for local ZFS pools or ZfsOverIscsi to make the intent explicit.
+	ZFS ProxmoxStorageType = "zfs"
 )
 
+// Content type values match PVE's content list.
+//
+// Breaking change: VmDiskImages used to be "image", which PVE does not know;
+// it is now "images".
 const (
 	Backup            ProxmoxStorageContentType = "backup"
 	Iso               ProxmoxStorageContentType = "iso"
-	VmDiskImages      ProxmoxStorageContentType = "image"
+	VmDiskImages      ProxmoxStorageContentType = "images"
 	CloudInitSnippets ProxmoxStorageContentType = "snippets"
 	LxcTemplates      ProxmoxStorageContentType = "vztmpl"
 	ContainerRootDir  ProxmoxStorageContentType = "rootdir"
+	DiskImports       ProxmoxStorageContentType = "import" // Disk images and OVAs to import guests from
 )
 
+// storageTypeAliases maps the names this package used before it matched PVE
+// onto the values PVE returns.
+var storageTypeAliases = map[string]ProxmoxStorageType{
+	"directory": Directory,
+	"lvm-thin":  LvmThin,
+	"zfs-iscsi": ZfsOverIscsi,
+}
+
+// ParseProxmoxStorageType returns the storage type for s, accepting both PVE's
+// names and the legacy aliases "directory", "lvm-thin" and "zfs-iscsi".
+func ParseProxmoxStorageType(s string) ProxmoxStorageType {
+	if t, ok := storageTypeAliases[s]; ok {
+		return t
+	}
+	return ProxmoxStorageType(s)
+}
+
+// ParseProxmoxStorageContent parses a comma-separated content list such as
+// "backup,iso,rootdir,images".
+func ParseProxmoxStorageContent(s string) ProxmoxStorageEnabledContent {
+	content := make(ProxmoxStorageEnabledContent)
+	for _, c := range strings.Split(s, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			content[ProxmoxStorageContentType(c)] = true
+		}
+	}
+	return content
+}
+
+// String formats the enabled content types as a sorted, comma-separated list.
+func (c ProxmoxStorageEnabledContent) String() string {
+	types := make([]string, 0, len(c))
+	for t, enabled := range c {
+		if enabled {
+			types = append(types, string(t))
+		}
+	}
+	sort.Strings(types)
+	return strings.Join(types, ",")
+}
+
+// Has reports whether content type t is enabled.
+func (c ProxmoxStorageEnabledContent) Has(t ProxmoxStorageContentType) bool {
+	return c[t]
+}
+
 type ProxmoxStoragePool struct {
 	Name         string                       `json:"name"`
 	Type         ProxmoxStorageType           `json:"type"`
 	Capabilities ProxmoxStorageEnabledContent `json:"capabilities"`
 	Path         string                       `json:"path"`
 	Server       string                       `json:"server"`
+	Export       string                       `json:"export,omitempty"`
+	VgName       string                       `json:"vgName,omitempty"`
+	ThinPool     string                       `json:"thinPool,omitempty"`
+	Nodes        []string                     `json:"nodes,omitempty"` // Empty means all nodes
+	PruneBackups *PrunePolicy                 `json:"pruneBackups,omitempty"`
+	Digest       string                       `json:"digest,omitempty"`
 	Shared       bool                         `json:"shared"`
-	TotalBytes   int                          `json:"totalBytes"`
-	Used         int                          `json:"used"`
+	Node         string                       `json:"node,omitempty"` // Set when status was read from a node
+	TotalBytes   int64                        `json:"totalBytes"`
+	Used         int64                        `json:"used"`
+	Avail        int64                        `json:"avail"`
+	UsedFraction float64                      `json:"usedFraction"`
+	Active       bool                         `json:"active"`
 	Enabled      bool                         `json:"enabled"`
+	// PruneBackupsRaw keeps a prune-backups setting that could not be parsed
+	// into PruneBackups; PruneBackupsErr says why.
+	PruneBackupsRaw string `json:"pruneBackupsRaw,omitempty"`
+	PruneBackupsErr error  `json:"-"`
+}
+
+// storageConfig is a single entry of a /storage response as PVE returns it.
+type storageConfig struct {
+	Storage      string `json:"storage"`
+	Type         string `json:"type"`
+	Content      string `json:"content"`
+	Path         string `json:"path"`
+	Server       string `json:"server"`
+	Export       string `json:"export"`
+	VgName       string `json:"vgname"`
+	ThinPool     string `json:"thinpool"`
+	Nodes        string `json:"nodes"`
+	PruneBackups string `json:"prune-backups"`
+	Digest       string `json:"digest"`
+	Shared       int    `json:"shared"`
+	Disable      int    `json:"disable"`
+}
+
+func (s *storageConfig) toPool() ProxmoxStoragePool {
+	pool := ProxmoxStoragePool{
+		Name:         s.Storage,
+		Type:         ParseProxmoxStorageType(s.Type),
+		Capabilities: ParseProxmoxStorageContent(s.Content),
+		Path:         s.Path,
+		Server:       s.Server,
+		Export:       s.Export,
+		VgName:       s.VgName,
+		ThinPool:     s.ThinPool,
+		Digest:       s.Digest,
+		Shared:       s.Shared == 1,
+		Enabled:      s.Disable == 0,
+	}
+	if s.Nodes != "" {
+		pool.Nodes = strings.Split(s.Nodes, ",")
+	}
+	if s.PruneBackups != "" {
+		policy, err := ParsePrunePolicy(s.PruneBackups)
+		if err != nil {
+			pool.PruneBackupsRaw = s.PruneBackups
+			pool.PruneBackupsErr = fmt.Errorf("storage %s: %w", s.Storage, err)
+		} else {
+			pool.PruneBackups = policy
+		}
+	}
+	return pool
+}
+
+// decodeStorageList decodes the data array of a /storage response, as captured
+// in storage.json.
+func decodeStorageList(data []byte) ([]ProxmoxStoragePool, error) {
+	var configs []storageConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("decoding storage list: %w", err)
+	}
+
+	pools := make([]ProxmoxStoragePool, 0, len(configs))
+	for i := range configs {
+		pools = append(pools, configs[i].toPool())
+	}
+	return pools, nil
+}
+
+// ListStorage returns the cluster-wide storage definitions.
+func (c *Client) ListStorage(ctx context.Context) ([]ProxmoxStoragePool, error) {
+	var data json.RawMessage
+	if err := c.do(ctx, http.MethodGet, apiStoragePath, nil, nil, false, &data); err != nil {
+		return nil, err
+	}
+	return decodeStorageList(data)
+}
+
+// GetStorage returns the storage definition with the given ID.
+func (c *Client) GetStorage(ctx context.Context, id string) (*ProxmoxStoragePool, error) {
+	var cfg storageConfig
+	if err := c.do(ctx, http.MethodGet, apiStoragePath+"/"+url.PathEscape(id), nil, nil, false, &cfg); err != nil {
+		return nil, err
+	}
+	pool := cfg.toPool()
+	return &pool, nil
+}
+
+// nodeStorageStatus is an entry of /nodes/{node}/storage or the response of
+// /nodes/{node}/storage/{storage}/status.
+type nodeStorageStatus struct {
+	Storage      string  `json:"storage"`
+	Type         string  `json:"type"`
+	Content      string  `json:"content"`
+	Shared       int     `json:"shared"`
+	Active       int     `json:"active"`
+	Enabled      int     `json:"enabled"`
+	Total        int64   `json:"total"`
+	Used         int64   `json:"used"`
+	Avail        int64   `json:"avail"`
+	UsedFraction float64 `json:"used_fraction"`
+}
+
+func (s *nodeStorageStatus) toPool(node string) ProxmoxStoragePool {
+	pool := ProxmoxStoragePool{
+		Name:         s.Storage,
+		Type:         ParseProxmoxStorageType(s.Type),
+		Capabilities: ParseProxmoxStorageContent(s.Content),
+		Shared:       s.Shared == 1,
+		Node:         node,
+		TotalBytes:   s.Total,
+		Used:         s.Used,
+		Avail:        s.Avail,
+		UsedFraction: s.UsedFraction,
+		Active:       s.Active == 1,
+		Enabled:      s.Enabled == 1,
+	}
+	if pool.UsedFraction == 0 && pool.TotalBytes > 0 {
+		pool.UsedFraction = float64(pool.Used) / float64(pool.TotalBytes)
+	}
+	return pool
+}
+
+// ListNodeStorage returns the storages available on node with their capacity
+// and usage. If content is set only storages that accept it are returned.
+func (c *Client) ListNodeStorage(ctx context.Context, node string, content ProxmoxStorageContentType) ([]ProxmoxStoragePool, error) {
+	path := fmt.Sprintf("%s/%s/storage", apiNodesPath, url.PathEscape(node))
+	if content != "" {
+		path += "?content=" + url.QueryEscape(string(content))
+	}
+
+	var statuses []nodeStorageStatus
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &statuses); err != nil {
+		return nil, err
+	}
+
+	pools := make([]ProxmoxStoragePool, 0, len(statuses))
+	for i := range statuses {
+		pools = append(pools, statuses[i].toPool(node))
+	}
+	return pools, nil
+}
+
+// GetNodeStorageStatus returns the capacity, usage and state of storage on node.
+func (c *Client) GetNodeStorageStatus(ctx context.Context, node, storage string) (*ProxmoxStoragePool, error) {
+	path := fmt.Sprintf("%s/%s/storage/%s/status", apiNodesPath, url.PathEscape(node), url.PathEscape(storage))
+
+	var status nodeStorageStatus
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &status); err != nil {
+		return nil, err
+	}
+	if status.Storage == "" {
+		status.Storage = storage
+	}
+	pool := status.toPool(node)
+	return &pool, nil
+}
+
+// RRDTimeframe is the period covered by an rrddata request.
+type RRDTimeframe string
+
+const (
+	RRDHour  RRDTimeframe = "hour"
+	RRDDay   RRDTimeframe = "day"
+	RRDWeek  RRDTimeframe = "week"
+	RRDMonth RRDTimeframe = "month"
+	RRDYear  RRDTimeframe = "year"
+)
+
+// RRDConsolidation selects how rrddata points are consolidated.
+type RRDConsolidation string
+
+const (
+	RRDAverage RRDConsolidation = "AVERAGE"
+	RRDMax     RRDConsolidation = "MAX"
+)
+
+// StorageRRDPoint is a single sample of a storage's usage history.
+type StorageRRDPoint struct {
+	Time  int64   `json:"time"`
+	Total float64 `json:"total,omitempty"`
+	Used  float64 `json:"used,omitempty"`
+}
+
+// GetStorageRRDData returns the usage history of storage on node. cf may be
+// empty to use the PVE default (AVERAGE).
+func (c *Client) GetStorageRRDData(ctx context.Context, node, storage string, timeframe RRDTimeframe, cf RRDConsolidation) ([]StorageRRDPoint, error) {
+	query := url.Values{}
+	query.Set("timeframe", string(timeframe))
+	if cf != "" {
+		query.Set("cf", string(cf))
+	}
+	path := fmt.Sprintf("%s/%s/storage/%s/rrddata?%s", apiNodesPath, url.PathEscape(node), url.PathEscape(storage), query.Encode())
+
+	var points []StorageRRDPoint
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &points); err != nil {
+		return nil, err
+	}
+	return points, nil
 }
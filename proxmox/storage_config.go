@@ -0,0 +1,475 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// StorageBackendConfig holds the options specific to one storage type.
+type StorageBackendConfig interface {
+	// Type returns the PVE storage type the config describes.
+	Type() ProxmoxStorageType
+	validate(verr *ValidationError)
+	encode(p *storageParams)
+}
+
+// StorageDefinition is a cluster-wide storage definition as managed through /storage.
+type StorageDefinition struct {
+	ID           string                       // Required: storage ID, e.g. "nfs-backups"
+	Content      ProxmoxStorageEnabledContent // Content types the storage may hold
+	Nodes        []string                     // Restrict to these nodes; empty means all nodes
+	Shared       *bool                        // Mark local-looking storage as shared (dir, lvm, zfs over iSCSI, ...)
+	Disable      *bool
+	PruneBackups *PrunePolicy // Backup retention
+	Digest       string       // Optional on update: reject the change if the config changed since this digest
+	Delete       []string     // Update only: options to remove, e.g. "nodes" or "prune-backups"
+	// Backend is required on create. On update it is only needed to change
+	// backend options.
+	Backend StorageBackendConfig
+}
+
+func (d *StorageDefinition) validate(create bool) error {
+	verr := &ValidationError{Resource: "StorageDefinition"}
+	if d.ID == "" {
+		verr.Add("storage", "required")
+	}
+	if create {
+		if d.Backend == nil {
+			verr.Add("type", "backend config required")
+		} else {
+			d.Backend.validate(verr)
+		}
+	}
+	if d.PruneBackups != nil {
+		d.PruneBackups.validate(verr)
+	}
+	if create && len(d.Delete) > 0 {
+		verr.Add("delete", "only valid on update")
+	}
+	return verr.Err()
+}
+
+// toParams encodes the definition. Fixed backend options, which PVE refuses to
+// change after creation, are only sent when create is set.
+func (d *StorageDefinition) toParams(create bool) url.Values {
+	p := &storageParams{values: url.Values{}, create: create}
+	if create {
+		p.set("storage", d.ID)
+		p.set("type", string(d.Backend.Type()))
+	}
+	if len(d.Content) > 0 {
+		p.set("content", d.Content.String())
+	}
+	if len(d.Nodes) > 0 {
+		p.set("nodes", strings.Join(d.Nodes, ","))
+	}
+	p.bool("shared", d.Shared)
+	p.bool("disable", d.Disable)
+	if d.PruneBackups != nil {
+		p.set("prune-backups", d.PruneBackups.String())
+	}
+	if !create {
+		p.set("digest", d.Digest)
+		p.set("delete", strings.Join(d.Delete, ","))
+	}
+	if d.Backend != nil {
+		d.Backend.encode(p)
+	}
+	return p.values
+}
+
+// storageParams collects form parameters for a storage create or update.
+type storageParams struct {
+	values url.Values
+	create bool
+}
+
+func (p *storageParams) set(key, value string) {
+	if value != "" {
+		p.values.Set(key, value)
+	}
+}
+
+// fixed sets an option PVE only accepts at creation time.
+func (p *storageParams) fixed(key, value string) {
+	if p.create {
+		p.set(key, value)
+	}
+}
+
+func (p *storageParams) bool(key string, value *bool) {
+	if value != nil {
+		p.values.Set(key, formatPveBool(*value))
+	}
+}
+
+func (p *storageParams) int(key string, value int) {
+	if value != 0 {
+		p.values.Set(key, fmt.Sprintf("%d", value))
+	}
+}
+
+// DirStorageConfig is a directory ("dir") storage.
+type DirStorageConfig struct {
+	Path         string // Required
+	MkDir        *bool  // Create the path if it does not exist
+	IsMountpoint string // "yes", or a path that must be mounted before the storage is used
+}
+
+func (c *DirStorageConfig) Type() ProxmoxStorageType { return Directory }
+
+func (c *DirStorageConfig) validate(verr *ValidationError) {
+	if c.Path == "" {
+		verr.Add("path", "required for %s storage", c.Type())
+	}
+}
+
+func (c *DirStorageConfig) encode(p *storageParams) {
+	p.fixed("path", c.Path)
+	p.bool("mkdir", c.MkDir)
+	p.set("is_mountpoint", c.IsMountpoint)
+}
+
+// LvmStorageConfig is an LVM volume group ("lvm") storage.
+type LvmStorageConfig struct {
+	VgName     string // Required
+	Base       string // Base volume, for LVM on top of iSCSI
+	SafeRemove *bool  // Zero out data when removing volumes
+}
+
+func (c *LvmStorageConfig) Type() ProxmoxStorageType { return LVM }
+
+func (c *LvmStorageConfig) validate(verr *ValidationError) {
+	if c.VgName == "" {
+		verr.Add("vgname", "required for %s storage", c.Type())
+	}
+}
+
+func (c *LvmStorageConfig) encode(p *storageParams) {
+	p.fixed("vgname", c.VgName)
+	p.fixed("base", c.Base)
+	p.bool("saferemove", c.SafeRemove)
+}
+
+// LvmThinStorageConfig is an LVM thin pool ("lvmthin") storage.
+type LvmThinStorageConfig struct {
+	VgName   string // Required
+	ThinPool string // Required
+}
+
+func (c *LvmThinStorageConfig) Type() ProxmoxStorageType { return LvmThin }
+
+func (c *LvmThinStorageConfig) validate(verr *ValidationError) {
+	if c.VgName == "" {
+		verr.Add("vgname", "required for %s storage", c.Type())
+	}
+	if c.ThinPool == "" {
+		verr.Add("thinpool", "required for %s storage", c.Type())
+	}
+}
+
+func (c *LvmThinStorageConfig) encode(p *storageParams) {
+	p.fixed("vgname", c.VgName)
+	p.fixed("thinpool", c.ThinPool)
+}
+
+// BtrfsStorageConfig is a BTRFS ("btrfs") storage.
+type BtrfsStorageConfig struct {
+	Path string // Required
+}
+
+func (c *BtrfsStorageConfig) Type() ProxmoxStorageType { return BTRFS }
+
+func (c *BtrfsStorageConfig) validate(verr *ValidationError) {
+	if c.Path == "" {
+		verr.Add("path", "required for %s storage", c.Type())
+	}
+}
+
+func (c *BtrfsStorageConfig) encode(p *storageParams) {
+	p.fixed("path", c.Path)
+}
+
+// NfsStorageConfig is an NFS export ("nfs") storage.
+type NfsStorageConfig struct {
+	Server  string // Required
+	Export  string // Required, e.g. "/mnt/tank/pve"
+	Path    string // Local mount point; PVE defaults to /mnt/pve/<id>
+	Options string // NFS mount options, e.g. "vers=4.2"
+}
+
+func (c *NfsStorageConfig) Type() ProxmoxStorageType { return NFS }
+
+func (c *NfsStorageConfig) validate(verr *ValidationError) {
+	if c.Server == "" {
+		verr.Add("server", "required for %s storage", c.Type())
+	}
+	if c.Export == "" {
+		verr.Add("export", "required for %s storage", c.Type())
+	}
+}
+
+func (c *NfsStorageConfig) encode(p *storageParams) {
+	p.fixed("server", c.Server)
+	p.fixed("export", c.Export)
+	p.fixed("path", c.Path)
+	p.set("options", c.Options)
+}
+
+// CifsStorageConfig is an SMB/CIFS share ("cifs") storage.
+type CifsStorageConfig struct {
+	Server     string // Required
+	Share      string // Required
+	Username   string
+	Password   string
+	Domain     string
+	SmbVersion string // "2.0", "2.1", "3", "3.0", "3.11" or "default"
+	Subdir     string
+	Path       string // Local mount point; PVE defaults to /mnt/pve/<id>
+	Options    string
+}
+
+func (c *CifsStorageConfig) Type() ProxmoxStorageType { return SmbCifs }
+
+func (c *CifsStorageConfig) validate(verr *ValidationError) {
+	if c.Server == "" {
+		verr.Add("server", "required for %s storage", c.Type())
+	}
+	if c.Share == "" {
+		verr.Add("share", "required for %s storage", c.Type())
+	}
+	if c.Password != "" && c.Username == "" {
+		verr.Add("username", "required when a password is set")
+	}
+}
+
+func (c *CifsStorageConfig) encode(p *storageParams) {
+	p.fixed("server", c.Server)
+	p.fixed("share", c.Share)
+	p.fixed("path", c.Path)
+	p.set("username", c.Username)
+	p.set("password", c.Password)
+	p.set("domain", c.Domain)
+	p.set("smbversion", c.SmbVersion)
+	p.set("subdir", c.Subdir)
+	p.set("options", c.Options)
+}
+
+// GlusterFsStorageConfig is a GlusterFS volume ("glusterfs") storage.
+type GlusterFsStorageConfig struct {
+	Server    string // Required
+	Server2   string // Backup server
+	Volume    string // Required
+	Transport string // "tcp", "rdma" or "unix"
+}
+
+func (c *GlusterFsStorageConfig) Type() ProxmoxStorageType { return GlusterFs }
+
+func (c *GlusterFsStorageConfig) validate(verr *ValidationError) {
+	if c.Server == "" {
+		verr.Add("server", "required for %s storage", c.Type())
+	}
+	if c.Volume == "" {
+		verr.Add("volume", "required for %s storage", c.Type())
+	}
+}
+
+func (c *GlusterFsStorageConfig) encode(p *storageParams) {
+	p.set("server", c.Server)
+	p.set("server2", c.Server2)
+	p.fixed("volume", c.Volume)
+	p.set("transport", c.Transport)
+}
+
+// CephFsStorageConfig is a CephFS ("cephfs") storage. Leave MonHost empty to
+// use the hyper-converged cluster's own Ceph.
+type CephFsStorageConfig struct {
+	MonHost  string // Monitor addresses separated by ';' or ' '
+	Username string
+	Keyring  string // Secret for external clusters
+	FsName   string
+	Subdir   string
+	Path     string // Local mount point
+	Fuse     *bool  // Mount with ceph-fuse instead of the kernel client
+}
+
+func (c *CephFsStorageConfig) Type() ProxmoxStorageType { return CephFs }
+
+func (c *CephFsStorageConfig) validate(verr *ValidationError) {
+	if c.Keyring != "" && c.MonHost == "" {
+		verr.Add("monhost", "required when a keyring for an external cluster is set")
+	}
+}
+
+func (c *CephFsStorageConfig) encode(p *storageParams) {
+	p.set("monhost", c.MonHost)
+	p.set("username", c.Username)
+	p.set("keyring", c.Keyring)
+	p.set("fs-name", c.FsName)
+	p.set("subdir", c.Subdir)
+	p.fixed("path", c.Path)
+	p.bool("fuse", c.Fuse)
+}
+
+// RbdStorageConfig is a Ceph RBD pool ("rbd") storage. Leave MonHost empty to
+// use the hyper-converged cluster's own Ceph.
+type RbdStorageConfig struct {
+	Pool      string // PVE defaults to "rbd"
+	MonHost   string
+	Username  string
+	Keyring   string
+	Namespace string
+	DataPool  string
+	Krbd      *bool // Map images through the kernel module
+}
+
+func (c *RbdStorageConfig) Type() ProxmoxStorageType { return RBD }
+
+func (c *RbdStorageConfig) validate(verr *ValidationError) {
+	if c.Keyring != "" && c.MonHost == "" {
+		verr.Add("monhost", "required when a keyring for an external cluster is set")
+	}
+}
+
+func (c *RbdStorageConfig) encode(p *storageParams) {
+	p.set("pool", c.Pool)
+	p.set("monhost", c.MonHost)
+	p.set("username", c.Username)
+	p.set("keyring", c.Keyring)
+	p.set("namespace", c.Namespace)
+	p.set("data-pool", c.DataPool)
+	p.bool("krbd", c.Krbd)
+}
+
+// ZfsOverIscsiStorageConfig is a ZFS over iSCSI ("zfs") storage.
+type ZfsOverIscsiStorageConfig struct {
+	Portal        string // Required
+	Target        string // Required: iSCSI target IQN
+	Pool          string // Required: ZFS pool on the target
+	IscsiProvider string // Required: "comstar", "istgt", "iet" or "LIO"
+	BlockSize     string // e.g. "8k"
+	Sparse        *bool
+	LioTpg        string // Required for the LIO provider
+	Nowritecache  *bool
+}
+
+func (c *ZfsOverIscsiStorageConfig) Type() ProxmoxStorageType { return ZfsOverIscsi }
+
+func (c *ZfsOverIscsiStorageConfig) validate(verr *ValidationError) {
+	for _, f := range []struct{ key, value string }{
+		{"portal", c.Portal},
+		{"target", c.Target},
+		{"pool", c.Pool},
+		{"iscsiprovider", c.IscsiProvider},
+	} {
+		if f.value == "" {
+			verr.Add(f.key, "required for %s storage", c.Type())
+		}
+	}
+	if c.IscsiProvider == "LIO" && c.LioTpg == "" {
+		verr.Add("lio_tpg", "required for the LIO provider")
+	}
+}
+
+func (c *ZfsOverIscsiStorageConfig) encode(p *storageParams) {
+	p.fixed("portal", c.Portal)
+	p.fixed("target", c.Target)
+	p.fixed("pool", c.Pool)
+	p.fixed("iscsiprovider", c.IscsiProvider)
+	p.set("blocksize", c.BlockSize)
+	p.bool("sparse", c.Sparse)
+	p.set("lio_tpg", c.LioTpg)
+	p.bool("nowritecache", c.Nowritecache)
+}
+
+// ZfsPoolStorageConfig is a local ZFS pool ("zfspool") storage.
+type ZfsPoolStorageConfig struct {
+	Pool       string // Required, e.g. "rpool/data"
+	BlockSize  string
+	Sparse     *bool
+	MountPoint string
+}
+
+func (c *ZfsPoolStorageConfig) Type() ProxmoxStorageType { return ZfsPool }
+
+func (c *ZfsPoolStorageConfig) validate(verr *ValidationError) {
+	if c.Pool == "" {
+		verr.Add("pool", "required for %s storage", c.Type())
+	}
+}
+
+func (c *ZfsPoolStorageConfig) encode(p *storageParams) {
+	p.fixed("pool", c.Pool)
+	p.set("blocksize", c.BlockSize)
+	p.bool("sparse", c.Sparse)
+	p.set("mountpoint", c.MountPoint)
+}
+
+// PbsStorageConfig is a Proxmox Backup Server datastore ("pbs") storage.
+type PbsStorageConfig struct {
+	Server        string // Required
+	Port          int
+	Datastore     string // Required
+	Namespace     string
+	Username      string // Required, e.g. "backup@pbs" or an API token ID
+	Password      string // Password or API token secret
+	Fingerprint   string // SHA-256 fingerprint of the PBS certificate
+	EncryptionKey string // JSON key, or "autogen" to generate one
+}
+
+func (c *PbsStorageConfig) Type() ProxmoxStorageType { return ProxmoxBackupServer }
+
+func (c *PbsStorageConfig) validate(verr *ValidationError) {
+	if c.Server == "" {
+		verr.Add("server", "required for %s storage", c.Type())
+	}
+	if c.Datastore == "" {
+		verr.Add("datastore", "required for %s storage", c.Type())
+	}
+	if c.Username == "" {
+		verr.Add("username", "required for %s storage", c.Type())
+	}
+}
+
+func (c *PbsStorageConfig) encode(p *storageParams) {
+	p.set("server", c.Server)
+	p.int("port", c.Port)
+	p.fixed("datastore", c.Datastore)
+	p.set("namespace", c.Namespace)
+	p.set("username", c.Username)
+	p.set("password", c.Password)
+	p.set("fingerprint", c.Fingerprint)
+	p.set("encryption-key", c.EncryptionKey)
+}
+
+// CreateStorage adds a storage definition to the cluster.
+func (c *Client) CreateStorage(ctx context.Context, def *StorageDefinition) error {
+	if def == nil {
+		return fmt.Errorf("StorageDefinition cannot be nil")
+	}
+	if err := def.validate(true); err != nil {
+		return err
+	}
+	return c.doForm(ctx, http.MethodPost, apiStoragePath, def.toParams(true), nil)
+}
+
+// UpdateStorage changes an existing storage definition. Only the options that
+// are set are sent; options PVE treats as fixed, such as an NFS export or a PBS
+// datastore, are never sent.
+func (c *Client) UpdateStorage(ctx context.Context, def *StorageDefinition) error {
+	if def == nil {
+		return fmt.Errorf("StorageDefinition cannot be nil")
+	}
+	if err := def.validate(false); err != nil {
+		return err
+	}
+	return c.doForm(ctx, http.MethodPut, apiStoragePath+"/"+url.PathEscape(def.ID), def.toParams(false), nil)
+}
+
+// DeleteStorage removes a storage definition. Data on the storage is not touched.
+func (c *Client) DeleteStorage(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, apiStoragePath+"/"+url.PathEscape(id), nil, nil, true, nil)
+}
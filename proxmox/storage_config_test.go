@@ -0,0 +1,65 @@
+package proxmox
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateStorageReenablesAndDeletes(t *testing.T) {
+	var form map[string][]string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		form = r.PostForm
+	})
+
+	def := &StorageDefinition{
+		ID:      "nfs-backups",
+		Disable: BoolPtr(false),
+		Delete:  []string{"nodes", "prune-backups"},
+		Backend: &NfsStorageConfig{},
+	}
+	if err := c.UpdateStorage(context.Background(), def); err != nil {
+		t.Fatalf("UpdateStorage: %v", err)
+	}
+	if got := form["disable"]; len(got) != 1 || got[0] != "0" {
+		t.Errorf("disable = %v, want [0]", got)
+	}
+	if got := form["delete"]; len(got) != 1 || got[0] != "nodes,prune-backups" {
+		t.Errorf("delete = %v, want [nodes,prune-backups]", got)
+	}
+}
+
+func TestStorageDefinitionDeleteOnlyOnUpdate(t *testing.T) {
+	def := &StorageDefinition{ID: "local", Delete: []string{"nodes"}, Backend: &DirStorageConfig{Path: "/srv"}}
+	if err := def.validate(true); err == nil {
+		t.Error("validate(create) accepted Delete")
+	}
+}
+
+func TestUpdateStorageWithoutBackend(t *testing.T) {
+	var form url.Values
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		form = r.PostForm
+	})
+
+	def := &StorageDefinition{ID: "nfs-backups", Nodes: []string{"pve1", "pve2"}}
+	if err := c.UpdateStorage(context.Background(), def); err != nil {
+		t.Fatalf("UpdateStorage: %v", err)
+	}
+	want := url.Values{"nodes": {"pve1,pve2"}}
+	if !reflect.DeepEqual(form, want) {
+		t.Errorf("form = %v, want %v", form, want)
+	}
+
+	if err := (&StorageDefinition{ID: "nfs-backups"}).validate(true); err == nil {
+		t.Error("validate(create) accepted a definition without backend")
+	}
+}
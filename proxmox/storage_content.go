@@ -0,0 +1,123 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// StorageVolume is a volume stored on a PVE storage.
+type StorageVolume struct {
+	Volid        string                    `json:"volid"` // e.g. "local:vztmpl/debian-12-standard_12.7-1_amd64.tar.zst"
+	Content      ProxmoxStorageContentType `json:"content"`
+	Format       string                    `json:"format"`
+	Size         int64                     `json:"size"`
+	Used         int64                     `json:"used,omitempty"` // Allocated bytes for thin volumes
+	CTime        int64                     `json:"ctime,omitempty"`
+	VmId         int                       `json:"vmid,omitempty"` // Owner guest, if any
+	Parent       string                    `json:"parent,omitempty"`
+	Notes        string                    `json:"notes,omitempty"`
+	Protected    PveBool                   `json:"protected,omitempty"`
+	Encrypted    string                    `json:"encrypted,omitempty"` // PBS key fingerprint
+	Verification *VolumeVerification       `json:"verification,omitempty"`
+}
+
+// VolumeVerification is the last PBS verification result of a backup volume.
+type VolumeVerification struct {
+	State string `json:"state"` // "ok" or "failed"
+	UPID  string `json:"upid"`
+}
+
+// FileName returns the last path element of the volume ID.
+func (v *StorageVolume) FileName() string {
+	_, name, _ := strings.Cut(v.Volid, ":")
+	return path.Base(name)
+}
+
+// StorageContentFilter narrows a storage content listing. Zero values match everything.
+type StorageContentFilter struct {
+	Content ProxmoxStorageContentType
+	VmId    int
+}
+
+func (f StorageContentFilter) query() string {
+	query := url.Values{}
+	if f.Content != "" {
+		query.Set("content", string(f.Content))
+	}
+	if f.VmId != 0 {
+		query.Set("vmid", fmt.Sprintf("%d", f.VmId))
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
+func storageContentPath(node, storage string) string {
+	return fmt.Sprintf("%s/%s/storage/%s/content", apiNodesPath, url.PathEscape(node), url.PathEscape(storage))
+}
+
+func storageVolumePath(node, storage, volid string) string {
+	return storageContentPath(node, storage) + "/" + url.PathEscape(volid)
+}
+
+// ListStorageContent returns the volumes on storage matching filter.
+func (c *Client) ListStorageContent(ctx context.Context, node, storage string, filter StorageContentFilter) ([]StorageVolume, error) {
+	var volumes []StorageVolume
+	if err := c.do(ctx, http.MethodGet, storageContentPath(node, storage)+filter.query(), nil, nil, false, &volumes); err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
+
+// VolumeAttributes are the attributes of a single volume.
+type VolumeAttributes struct {
+	Path      string  `json:"path"`
+	Format    string  `json:"format"`
+	Size      int64   `json:"size"`
+	Used      int64   `json:"used"`
+	Notes     string  `json:"notes,omitempty"`
+	Protected PveBool `json:"protected,omitempty"`
+}
+
+// GetVolumeAttributes returns the attributes of volid on storage.
+func (c *Client) GetVolumeAttributes(ctx context.Context, node, storage, volid string) (*VolumeAttributes, error) {
+	var attrs VolumeAttributes
+	if err := c.do(ctx, http.MethodGet, storageVolumePath(node, storage, volid), nil, nil, false, &attrs); err != nil {
+		return nil, err
+	}
+	return &attrs, nil
+}
+
+// UpdateVolumeAttributes sets the notes and/or protection flag of volid. Nil
+// fields are left unchanged; an empty notes string clears the notes.
+func (c *Client) UpdateVolumeAttributes(ctx context.Context, node, storage, volid string, notes *string, protected *bool) error {
+	params := url.Values{}
+	if notes != nil {
+		params.Set("notes", *notes)
+	}
+	if protected != nil {
+		params.Set("protected", formatPveBool(*protected))
+	}
+	if len(params) == 0 {
+		return fmt.Errorf("no volume attributes to update")
+	}
+	return c.doForm(ctx, http.MethodPut, storageVolumePath(node, storage, volid), params, nil)
+}
+
+// DeleteVolume deletes volid from storage. PVE versions that delete without a
+// worker return no UPID, in which case the returned task is nil.
+func (c *Client) DeleteVolume(ctx context.Context, node, storage, volid string) (*Task, error) {
+	var upid string
+	if err := c.do(ctx, http.MethodDelete, storageVolumePath(node, storage, volid), nil, nil, true, &upid); err != nil {
+		return nil, err
+	}
+	if upid == "" {
+		return nil, nil
+	}
+	return c.newTask(node, upid), nil
+}
@@ -0,0 +1,94 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DownloadURLOptions are the parameters for having a node download a file
+// straight into storage.
+type DownloadURLOptions struct {
+	URL                string                    // Required
+	Content            ProxmoxStorageContentType // Required: Iso or LxcTemplates
+	Filename           string                    // Required: target file name on the storage
+	Checksum           string
+	ChecksumAlgorithm  ChecksumAlgorithm
+	Compression        string // Decompress after download: "gz", "lzo", "zst" or "bz2" (ISO only)
+	VerifyCertificates *bool  // PVE defaults to verifying TLS certificates
+}
+
+func (o *DownloadURLOptions) validate() error {
+	verr := &ValidationError{Resource: "DownloadURLOptions"}
+	if o.URL == "" {
+		verr.Add("url", "required")
+	}
+	if o.Content == "" {
+		verr.Add("content", "required")
+	}
+	if o.Filename == "" {
+		verr.Add("filename", "required")
+	}
+	if (o.Checksum == "") != (o.ChecksumAlgorithm == "") {
+		verr.Add("checksum", "checksum and checksum-algorithm must be set together")
+	}
+	if o.Compression != "" && o.Content != Iso {
+		verr.Add("compression", "decompression is only supported for %s content", Iso)
+	}
+	return verr.Err()
+}
+
+func (o *DownloadURLOptions) toParams() url.Values {
+	params := url.Values{}
+	params.Set("url", o.URL)
+	params.Set("content", string(o.Content))
+	params.Set("filename", o.Filename)
+	if o.Checksum != "" {
+		params.Set("checksum", o.Checksum)
+		params.Set("checksum-algorithm", string(o.ChecksumAlgorithm))
+	}
+	if o.Compression != "" {
+		params.Set("compression", o.Compression)
+	}
+	if o.VerifyCertificates != nil {
+		params.Set("verify-certificates", formatPveBool(*o.VerifyCertificates))
+	}
+	return params
+}
+
+// DownloadURLToStorage has node fetch opts.URL directly into storage.
+func (c *Client) DownloadURLToStorage(ctx context.Context, node, storage string, opts DownloadURLOptions) (*Task, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("%s/%s/storage/%s/download-url", apiNodesPath, url.PathEscape(node), url.PathEscape(storage))
+	var upid string
+	if err := c.doForm(ctx, http.MethodPost, path, opts.toParams(), &upid); err != nil {
+		return nil, err
+	}
+	return c.newTask(node, upid), nil
+}
+
+// URLMetadata is the result of a query-url-metadata preflight.
+type URLMetadata struct {
+	Filename string `json:"filename,omitempty"`
+	MimeType string `json:"mimetype,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+}
+
+// QueryURLMetadata has node issue a HEAD request for rawURL and returns the
+// file name, MIME type and size it reports.
+func (c *Client) QueryURLMetadata(ctx context.Context, node, rawURL string, verifyCertificates bool) (*URLMetadata, error) {
+	query := url.Values{}
+	query.Set("url", rawURL)
+	query.Set("verify-certificates", formatPveBool(verifyCertificates))
+	path := fmt.Sprintf("%s/%s/query-url-metadata?%s", apiNodesPath, url.PathEscape(node), query.Encode())
+
+	var meta URLMetadata
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
@@ -0,0 +1,84 @@
+package proxmox
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeStorageListGolden(t *testing.T) {
+	raw, err := os.ReadFile("storage.json")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		t.Fatalf("unwrapping fixture: %v", err)
+	}
+
+	pools, err := decodeStorageList(envelope.Data)
+	if err != nil {
+		t.Fatalf("decodeStorageList: %v", err)
+	}
+
+	const digest = "6bc1ab31aefc34869b27ef17f9b295cd09a1e594"
+	allContent := ParseProxmoxStorageContent("backup,images,iso,rootdir,snippets,vztmpl")
+	keepAll := &PrunePolicy{KeepAll: true}
+	want := []ProxmoxStoragePool{
+		{Name: "local", Type: Directory, Capabilities: allContent, Path: "/var/lib/vz", Digest: digest, Enabled: true},
+		{Name: "storageprox", Type: Directory, Capabilities: allContent, Path: "/mnt/data/backup", PruneBackups: keepAll, Digest: digest, Enabled: true},
+		{Name: "wal_backups", Type: NFS, Capabilities: allContent, Path: "/mnt/pve/wal_backups", Server: "10.0.0.8", Export: "/mnt/trahan-nas", PruneBackups: keepAll, Digest: digest, Shared: true, Enabled: true},
+		{Name: "local-lvm", Type: LvmThin, Capabilities: ParseProxmoxStorageContent("images,rootdir"), VgName: "pve", ThinPool: "data", Digest: digest, Enabled: true},
+		{Name: "hddata", Type: Directory, Capabilities: allContent, Path: "/mnt/hddata", PruneBackups: keepAll, Digest: digest, Enabled: true},
+	}
+	if len(pools) != len(want) {
+		t.Fatalf("decoded %d pools, want %d", len(pools), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(pools[i], want[i]) {
+			t.Errorf("pool %d:\n got %+v\nwant %+v", i, pools[i], want[i])
+		}
+	}
+}
+
+func TestDecodeStorageListInvalidPrunePolicy(t *testing.T) {
+	pools, err := decodeStorageList([]byte(`[{"storage":"local","type":"dir","prune-backups":"keep-often=1"},{"storage":"nfs","type":"nfs","prune-backups":"keep-last=3"}]`))
+	if err != nil {
+		t.Fatalf("decodeStorageList: %v", err)
+	}
+	if len(pools) != 2 {
+		t.Fatalf("got %d pools, want 2", len(pools))
+	}
+	if pools[0].PruneBackupsErr == nil || pools[0].PruneBackupsRaw != "keep-often=1" || pools[0].PruneBackups != nil {
+		t.Errorf("invalid entry = %+v", pools[0])
+	}
+	if pools[1].PruneBackupsErr != nil || pools[1].PruneBackups == nil || pools[1].PruneBackups.KeepLast != 3 {
+		t.Errorf("valid entry = %+v", pools[1])
+	}
+}
+
+func TestParseProxmoxStorageType(t *testing.T) {
+	tests := map[string]ProxmoxStorageType{
+		"zfs-iscsi": ZfsOverIscsi,
+		"zfs":       ZfsOverIscsi,
+		"zfspool":   ZfsPool,
+		"directory": Directory,
+		"dir":       Directory,
+		"lvm-thin":  LvmThin,
+		"lvmthin":   LvmThin,
+	}
+	for in, want := range tests {
+		if got := ParseProxmoxStorageType(in); got != want {
+			t.Errorf("ParseProxmoxStorageType(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if ZFS != ZfsOverIscsi {
+		t.Errorf("deprecated ZFS = %q, want ZfsOverIscsi (%q)", ZFS, ZfsOverIscsi)
+	}
+	if !ParseProxmoxStorageContent("images,rootdir").Has(VmDiskImages) {
+		t.Errorf("VmDiskImages = %q does not match PVE's images content", VmDiskImages)
+	}
+}
@@ -0,0 +1,56 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUploadToStorageRequiresSize(t *testing.T) {
+	called := false
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	// io.MultiReader hides the size of the underlying reader.
+	r := io.MultiReader(strings.NewReader("payload"))
+	if _, err := c.UploadToStorage(context.Background(), "pve1", "local", Iso, "test.iso", r); err == nil {
+		t.Fatal("UploadToStorage succeeded without a known size")
+	}
+	if called {
+		t.Error("request sent although the size is unknown")
+	}
+}
+
+func TestUploadToStorageSendsContentLength(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if len(r.TransferEncoding) > 0 || r.ContentLength != int64(len(body)) {
+			t.Errorf("ContentLength = %d, TransferEncoding = %v, body %d bytes", r.ContentLength, r.TransferEncoding, len(body))
+		}
+		fmt.Fprint(w, `{"data":"UPID:pve1:00000001:00000001:00000001:imgcopy::root@pam:"}`)
+	})
+
+	r := io.MultiReader(strings.NewReader("payload"))
+	if _, err := c.UploadToStorage(context.Background(), "pve1", "local", Iso, "test.iso", r, WithUploadSize(7)); err != nil {
+		t.Fatalf("UploadToStorage: %v", err)
+	}
+}
+
+func TestUploadToStorageRejectsContentType(t *testing.T) {
+	called := false
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	r := strings.NewReader("#cloud-config\n")
+	if _, err := c.UploadToStorage(context.Background(), "pve1", "local", CloudInitSnippets, "user.yaml", r); err == nil {
+		t.Fatal("UploadToStorage accepted a snippets upload")
+	}
+	if called || r.Len() != len("#cloud-config\n") {
+		t.Error("snippets upload was sent or read")
+	}
+}
@@ -3,8 +3,8 @@ package proxmox
 import (
 	"encoding/json"
 	"fmt"
-	"log/slog"
-	"os"
+	"strconv"
+	"strings"
 )
 
 // QemuVm represents basic information about a VM from Proxmox.
@@ -24,12 +24,12 @@ type QemuVm struct {
 	DiskWrite         int     `json:"diskwrite,omitempty"`
 	Node              string  `json:"node,omitempty"`
 	PID               int     `json:"pid,omitempty"`
-	PresureCpuFull    int     `json:"pressurecpufull,omitempty"`
-	PresureCpuSome    int     `json:"pressurecpusome,omitempty"`
-	PresureIoFull     int     `json:"pressureiofull,omitempty"`
-	PresureIoSome     int     `json:"pressureiosome,omitempty"`
-	PresureMemoryFull int     `json:"pressurememoryfull,omitempty"`
-	PresureMemorySome int     `json:"pressurememorysome,omitempty"`
+	PresureCpuFull    float64 `json:"pressurecpufull,omitempty"`
+	PresureCpuSome    float64 `json:"pressurecpusome,omitempty"`
+	PresureIoFull     float64 `json:"pressureiofull,omitempty"`
+	PresureIoSome     float64 `json:"pressureiosome,omitempty"`
+	PresureMemoryFull float64 `json:"pressurememoryfull,omitempty"`
+	PresureMemorySome float64 `json:"pressurememorysome,omitempty"`
 	QmStatus          string  `json:"qmstatus,omitempty"`
 	RunningMachine    string  `json:"running-machine,omitempty"`
 	RunningQemu       string  `json:"running-qemu,omitempty"`
@@ -79,65 +79,151 @@ type Auth struct {
 }
 
 type LxcContainer struct {
-	Node          string   `json:"node,omitempty"`       // Used in URL, not payload
-	VmId          int      `json:"vmid,omitempty"`       // Required
-	Hostname      string   `json:"hostname,omitempty"`   // Required
-	Password      string   `json:"password,omitempty"`   // Required if not using SSH key
-	OsTemplate    string   `json:"ostemplate,omitempty"` // Required (e.g., "local:vztmpl/ubuntu-22.04-standard_22.04-1_amd64.tar.zst")
-	Storage       string   `json:"storage,omitempty"`    // Required (storage ID for rootfs)
-	RootFsSize    string   `json:"rootfs,omitempty"`     // Required (e.g., "8G")
-	Memory        int      `json:"memory,omitempty"`     // RAM in MB
-	Swap          int      `json:"swap,omitempty"`       // Swap in MB
-	Cores         int      `json:"cores,omitempty"`      // CPU cores
-	CpuLimit      int      `json:"cpulimit,omitempty"`   // Limit in % of total
-	CpuUnits      int      `json:"cpuunits,omitempty"`   // Relative CPU weight
-	Net0          string   `json:"net0,omitempty"`       // Network config string
-	Bridge        string   `json:"bridge,omitempty"`     // Optional, if setting bridge separately
-	Nameserver    string   `json:"nameserver,omitempty"` // DNS
-	Searchdomain  string   `json:"searchdomain,omitempty"`
-	Pool          string   `json:"pool,omitempty"` // Optional pool
-	Description   string   `json:"description,omitempty"`
-	Unprivileged  string   `json:"unprivileged,omitempty"` // 1 or 0
-	Start         string   `json:"start,omitempty"`        // 1 to auto-start
-	BwLimit       int      `json:"bwlimit,omitempty"`
-	Arch          string   `json:"arch,omitempty"`            // e.g., "amd64"
-	Cmode         string   `json:"cmode,omitempty"`           // e.g., "tty"
-	Console       string   `json:"console,omitempty"`         // 0 or 1
-	Debug         int      `json:"debug,omitempty"`           // 0 or 1
-	Features      string   `json:"features,omitempty"`        // Comma-separated list
-	Startup       string   `json:"startup,omitempty"`         // Startup order string
-	Tags          string   `json:"tags,omitempty"`            // Comma-separated tags
-	SshPublicKeys []string `json:"ssh_public_keys,omitempty"` // SSH keys string
+	Node          string            `json:"node,omitempty"`        // Used in URL, not payload
+	VmId          int               `json:"vmid,omitempty"`        // Required
+	Hostname      string            `json:"hostname,omitempty"`    // Required
+	Password      string            `json:"password,omitempty"`    // Required if not using SSH key
+	OsTemplate    string            `json:"ostemplate,omitempty"`  // Required (e.g., "local:vztmpl/ubuntu-22.04-standard_22.04-1_amd64.tar.zst")
+	Storage       string            `json:"storage,omitempty"`     // Required (storage ID for rootfs)
+	RootFsSize    string            `json:"rootfs,omitempty"`      // Required (e.g., "8G", "512M"; a bare number is GiB)
+	Memory        int               `json:"memory,omitempty"`      // RAM in MB
+	Swap          int               `json:"swap,omitempty"`        // Swap in MB
+	Cores         int               `json:"cores,omitempty"`       // CPU cores
+	CpuLimit      int               `json:"cpulimit,omitempty"`    // Limit in % of total
+	CpuUnits      int               `json:"cpuunits,omitempty"`    // Relative CPU weight
+	Networks      []LxcNetInterface `json:"networks,omitempty"`    // netN interfaces
+	MountPoints   []LxcMountPoint   `json:"mountpoints,omitempty"` // mpN mount points
+	Bridge        string            `json:"bridge,omitempty"`      // Optional, if setting bridge separately
+	Nameserver    string            `json:"nameserver,omitempty"`  // DNS
+	Searchdomain  string            `json:"searchdomain,omitempty"`
+	Pool          string            `json:"pool,omitempty"` // Optional pool
+	Description   string            `json:"description,omitempty"`
+	Unprivileged  bool              `json:"unprivileged,omitempty"`
+	Start         bool              `json:"start,omitempty"` // Start after creation
+	BwLimit       int               `json:"bwlimit,omitempty"`
+	Arch          string            `json:"arch,omitempty"`    // e.g., "amd64"
+	Cmode         string            `json:"cmode,omitempty"`   // e.g., "tty"
+	Console       *bool             `json:"console,omitempty"` // PVE defaults to enabled; nil leaves it unset
+	Debug         int               `json:"debug,omitempty"`   // 0 or 1
+	Features      *LxcFeatures      `json:"features,omitempty"`
+	Startup       string            `json:"startup,omitempty"`         // Startup order string
+	Tags          string            `json:"tags,omitempty"`            // Comma-separated tags
+	SshPublicKeys []string          `json:"ssh_public_keys,omitempty"` // SSH keys string
+	// Status holds runtime state when the container was returned by a list or status call.
+	Status *LxcStatus `json:"status,omitempty"`
+	// Raw holds additional config fields not mapped above.
+	Raw map[string]string `json:"raw,omitempty"`
 }
 
-func (lxc *LxcContainer) ToFormParams() map[string]string {
-	params := make(map[string]string)
+// LxcStatus represents the runtime state of a container from Proxmox.
+type LxcStatus struct {
+	Vmid      int     `json:"vmid"`
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Type      string  `json:"type,omitempty"`
+	Node      string  `json:"node,omitempty"`
+	CPU       float64 `json:"cpu,omitempty"`
+	CPUs      float64 `json:"cpus,omitempty"`
+	Mem       int64   `json:"mem,omitempty"`
+	MaxMem    int64   `json:"maxmem,omitempty"`
+	Swap      int64   `json:"swap,omitempty"`
+	MaxSwap   int64   `json:"maxswap,omitempty"`
+	Disk      int64   `json:"disk,omitempty"`
+	MaxDisk   int64   `json:"maxdisk,omitempty"`
+	NetIn     int64   `json:"netin,omitempty"`
+	NetOut    int64   `json:"netout,omitempty"`
+	DiskRead  int64   `json:"diskread,omitempty"`
+	DiskWrite int64   `json:"diskwrite,omitempty"`
+	PID       int     `json:"pid,omitempty"`
+	Lock      string  `json:"lock,omitempty"`
+	Tags      string  `json:"tags,omitempty"`
+	Template  int     `json:"template,omitempty"`
+	Uptime    int64   `json:"uptime,omitempty"`
+}
+
+// Validate checks the fields required to create a container and returns a
+// *ValidationError listing every problem found.
+func (lxc *LxcContainer) Validate() error {
+	verr := &ValidationError{Resource: "LxcContainer"}
 
-	if lxc.VmId != 0 {
-		params["vmid"] = fmt.Sprintf("%d", lxc.VmId)
+	if lxc.VmId <= 0 {
+		verr.Add("vmid", "must be a positive integer, got %d", lxc.VmId)
+	}
+	if lxc.Hostname == "" {
+		verr.Add("hostname", "required")
+	}
+	if lxc.OsTemplate == "" {
+		verr.Add("ostemplate", "required")
+	}
+	if lxc.Storage == "" {
+		verr.Add("storage", "required")
+	}
+	if lxc.Password == "" && len(lxc.SshPublicKeys) == 0 {
+		verr.Add("password", "password or ssh_public_keys required")
+	}
+	for i, key := range lxc.SshPublicKeys {
+		if strings.TrimSpace(key) == "" {
+			verr.Add("ssh_public_keys", "key %d is empty", i)
+		}
+	}
+	if lxc.RootFsSize != "" {
+		if _, err := rootFsGiB(lxc.RootFsSize); err != nil {
+			verr.Add("rootfs", "%v", err)
+		}
 	}
-	if lxc.Hostname != "" {
-		params["hostname"] = lxc.Hostname
+	lxc.validateNetworksAndMounts(verr)
+	if lxc.Features != nil {
+		lxc.Features.validate(verr, lxc.Unprivileged)
 	}
+
+	return verr.Err()
+}
+
+// rootFsGiB converts a root disk size such as "8G" to the bare GiB number PVE
+// expects when allocating a new volume. A size without unit is taken as GiB.
+func rootFsGiB(size string) (string, error) {
+	gib := size
+	if _, err := strconv.ParseFloat(size, 64); err != nil {
+		if gib, err = sizeToGiB(size); err != nil {
+			return "", err
+		}
+	}
+	if n, _ := strconv.ParseFloat(gib, 64); n <= 0 {
+		return "", fmt.Errorf("size %q must be positive", size)
+	}
+	return gib, nil
+}
+
+// ToFormParams validates the container and encodes every field as API form
+// parameters for POST /nodes/{node}/lxc.
+func (lxc *LxcContainer) ToFormParams() (map[string]string, error) {
+	if err := lxc.Validate(); err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]string)
+
+	params["vmid"] = fmt.Sprintf("%d", lxc.VmId)
+	params["hostname"] = lxc.Hostname
+	params["ostemplate"] = lxc.OsTemplate
+	params["storage"] = lxc.Storage
+
 	if lxc.Password != "" {
 		params["password"] = lxc.Password
 	}
-	if lxc.OsTemplate != "" {
-		params["ostemplate"] = lxc.OsTemplate
-	}
 	if len(lxc.SshPublicKeys) >= 1 {
 		keysString, err := lxc.ParseSshPublicKeySlice()
 		if err != nil {
-			slog.Error("Error parsing ssh-public-keys", "error", err.Error(), slog.Any("keys", lxc.SshPublicKeys))
-			os.Exit(1)
+			return nil, fmt.Errorf("parsing ssh-public-keys: %w", err)
 		}
 		params["ssh-public-keys"] = keysString
 	}
-	if lxc.Storage != "" {
-		params["storage"] = lxc.Storage
-	}
-	if lxc.RootFsSize != "" && lxc.Storage != "" {
-		params["rootfs"] = fmt.Sprintf("%s:%s", lxc.Storage, lxc.RootFsSize)
+	if lxc.RootFsSize != "" {
+		size, err := rootFsGiB(lxc.RootFsSize)
+		if err != nil {
+			return nil, err
+		}
+		params["rootfs"] = fmt.Sprintf("%s:%s", lxc.Storage, size)
 	}
 	if lxc.Memory != 0 {
 		params["memory"] = fmt.Sprintf("%d", lxc.Memory)
@@ -154,8 +240,32 @@ func (lxc *LxcContainer) ToFormParams() map[string]string {
 	if lxc.CpuUnits != 0 {
 		params["cpuunits"] = fmt.Sprintf("%d", lxc.CpuUnits)
 	}
-	if lxc.Net0 != "" {
-		params["net0"] = lxc.Net0
+	for _, n := range lxc.networksWithBridge() {
+		params[n.Key()] = n.String()
+	}
+	for _, mp := range lxc.MountPoints {
+		params[mp.Key()] = mp.String()
+	}
+	if lxc.Nameserver != "" {
+		params["nameserver"] = lxc.Nameserver
+	}
+	if lxc.Searchdomain != "" {
+		params["searchdomain"] = lxc.Searchdomain
+	}
+	if lxc.Pool != "" {
+		params["pool"] = lxc.Pool
+	}
+	if lxc.Description != "" {
+		params["description"] = lxc.Description
+	}
+	if lxc.Unprivileged {
+		params["unprivileged"] = "1"
+	}
+	if lxc.Start {
+		params["start"] = "1"
+	}
+	if lxc.BwLimit != 0 {
+		params["bwlimit"] = fmt.Sprintf("%d", lxc.BwLimit)
 	}
 	if lxc.Arch != "" {
 		params["arch"] = lxc.Arch
@@ -163,15 +273,27 @@ func (lxc *LxcContainer) ToFormParams() map[string]string {
 	if lxc.Cmode != "" {
 		params["cmode"] = lxc.Cmode
 	}
-	if lxc.Start != "" {
-		params["start"] = lxc.Start
+	if lxc.Console != nil {
+		params["console"] = formatPveBool(*lxc.Console)
 	}
-	if lxc.Console != "" {
-		params["console"] = lxc.Console
+	if lxc.Debug != 0 {
+		params["debug"] = fmt.Sprintf("%d", lxc.Debug)
 	}
-	if lxc.Unprivileged != "" {
-		params["unprivileged"] = lxc.Unprivileged
+	if lxc.Features != nil {
+		params["features"] = lxc.Features.String()
+	}
+	if lxc.Startup != "" {
+		params["startup"] = lxc.Startup
+	}
+	if lxc.Tags != "" {
+		params["tags"] = lxc.Tags
+	}
+
+	for k, v := range lxc.Raw {
+		if _, set := params[k]; !set && v != "" {
+			params[k] = v
+		}
 	}
 
-	return params
+	return params, nil
 }
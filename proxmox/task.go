@@ -0,0 +1,193 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultTaskPollInterval = 2 * time.Second
+
+// Task is a handle to an asynchronous Proxmox task identified by its UPID.
+type Task struct {
+	UPID   string
+	Node   string
+	client *Client
+}
+
+// TaskStatus is the decoded response of /nodes/{node}/tasks/{upid}/status.
+type TaskStatus struct {
+	UPID       string `json:"upid"`
+	Node       string `json:"node"`
+	Type       string `json:"type"`
+	ID         string `json:"id,omitempty"`
+	User       string `json:"user"`
+	PID        int    `json:"pid"`
+	PStart     int64  `json:"pstart"`
+	StartTime  int64  `json:"starttime"`
+	Status     string `json:"status"`               // "running" or "stopped"
+	ExitStatus string `json:"exitstatus,omitempty"` // "OK" on success, set once stopped
+}
+
+// TaskLogLine is a single line of a task log.
+type TaskLogLine struct {
+	N int    `json:"n"`
+	T string `json:"t"`
+}
+
+// newTask returns a Task for upid. If node is empty it is taken from the UPID.
+func (c *Client) newTask(node, upid string) *Task {
+	if node == "" {
+		node = nodeFromUPID(upid)
+	}
+	return &Task{UPID: upid, Node: node, client: c}
+}
+
+// Task returns a handle for an existing task UPID.
+func (c *Client) Task(upid string) *Task {
+	return c.newTask("", upid)
+}
+
+// nodeFromUPID extracts the node name from "UPID:node:pid:pstart:starttime:type:id:user:".
+func nodeFromUPID(upid string) string {
+	parts := strings.Split(upid, ":")
+	if len(parts) < 2 || parts[0] != "UPID" {
+		return ""
+	}
+	return parts[1]
+}
+
+func (t *Task) path(sub string) string {
+	return fmt.Sprintf("%s/%s/tasks/%s%s", apiNodesPath, url.PathEscape(t.Node), url.PathEscape(t.UPID), sub)
+}
+
+// IsRunning reports whether the task has not finished yet.
+func (s *TaskStatus) IsRunning() bool {
+	return s.Status == "running"
+}
+
+// Succeeded reports whether the task finished with exit status OK.
+func (s *TaskStatus) Succeeded() bool {
+	return s.Status == "stopped" && s.ExitStatus == "OK"
+}
+
+// Status returns the current status of the task.
+func (t *Task) Status(ctx context.Context) (*TaskStatus, error) {
+	var status TaskStatus
+	if err := t.client.do(ctx, http.MethodGet, t.path("/status"), nil, nil, false, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Wait polls the task until it stops or ctx is done. A non-positive interval
+// uses the default poll interval. An error is returned if the task did not
+// finish with exit status OK.
+func (t *Task) Wait(ctx context.Context, interval time.Duration) (*TaskStatus, error) {
+	var status *TaskStatus
+	err := poll(ctx, interval, func() (bool, error) {
+		var err error
+		if status, err = t.Status(ctx); err != nil {
+			return false, err
+		}
+		return !status.IsRunning(), nil
+	})
+	if err != nil {
+		return status, err // the last status if ctx is done, nil otherwise
+	}
+	if !status.Succeeded() {
+		return status, fmt.Errorf("task %s failed: %s", t.UPID, status.ExitStatus)
+	}
+	return status, nil
+}
+
+// poll calls fn every interval (the default poll interval if non-positive)
+// until it reports done or fails, or ctx is done.
+func poll(ctx context.Context, interval time.Duration, fn func() (done bool, err error)) error {
+	if interval <= 0 {
+		interval = defaultTaskPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if done, err := fn(); done || err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Log returns up to limit lines of the task log starting at line start.
+func (t *Task) Log(ctx context.Context, start, limit int) ([]TaskLogLine, error) {
+	query := url.Values{}
+	query.Set("start", fmt.Sprintf("%d", start))
+	if limit > 0 {
+		query.Set("limit", fmt.Sprintf("%d", limit))
+	}
+
+	var lines []TaskLogLine
+	if err := t.client.do(ctx, http.MethodGet, t.path("/log")+"?"+query.Encode(), nil, nil, false, &lines); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// taskLogPageSize is the number of log lines fetched per request when streaming.
+const taskLogPageSize = 500
+
+// StreamLog writes the task log to w as it is produced, polling every
+// interval (the default poll interval if non-positive) until the task has
+// stopped and its log has been read completely, or ctx is done.
+func (t *Task) StreamLog(ctx context.Context, w io.Writer, interval time.Duration) error {
+	start := 0
+	return poll(ctx, interval, func() (bool, error) {
+		// Check the status before reading, so lines written just before the
+		// task stopped are still picked up by the final read.
+		status, err := t.Status(ctx)
+		if err != nil {
+			return false, err
+		}
+		for {
+			lines, err := t.Log(ctx, start, taskLogPageSize)
+			if err != nil {
+				return false, err
+			}
+			// PVE returns a single placeholder line for an empty log.
+			if start == 0 && len(lines) == 1 && lines[0].T == "no content" {
+				lines = nil
+			}
+			if err := writeLogLines(w, lines); err != nil {
+				return false, err
+			}
+			start += len(lines)
+			if len(lines) < taskLogPageSize {
+				break
+			}
+		}
+		return !status.IsRunning(), nil
+	})
+}
+
+func writeLogLines(w io.Writer, lines []TaskLogLine) error {
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line.T); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop requests the task be aborted.
+func (t *Task) Stop(ctx context.Context) error {
+	return t.client.do(ctx, http.MethodDelete, t.path(""), nil, nil, true, nil)
+}
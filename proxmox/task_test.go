@@ -0,0 +1,30 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestTaskPathEscapesUPIDOnce(t *testing.T) {
+	const upid = "UPID:pve1:000A1B2C:0012D3E4:6650A1B2:vzcreate:101:root@pam!ci:"
+	want := "/api2/json/nodes/pve1/tasks/" + upid + "/status"
+
+	var gotPath, gotRaw string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotRaw = r.URL.Path, r.URL.EscapedPath()
+		fmt.Fprint(w, `{"data":{"status":"stopped","exitstatus":"OK"}}`)
+	})
+
+	status, err := c.Task(upid).Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !status.Succeeded() {
+		t.Errorf("Succeeded() = false for %+v", status)
+	}
+	if gotPath != want {
+		t.Errorf("server saw path %q (raw %q), want %q", gotPath, gotRaw, want)
+	}
+}
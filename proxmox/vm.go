@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -185,44 +184,17 @@ func (c *Client) SetCores(ctx context.Context, node string, vmid int, cores int)
 	return c.UpdateVMConfig(ctx, node, vmid, cfg)
 }
 
+// ListVMs returns the QEMU VMs of node; full requests the full status of each VM.
 func (c *Client) ListVMs(ctx context.Context, node string, full bool) ([]QemuVm, error) {
-	var fullInt int = 0
+	fullInt := 0
 	if full {
-		fullInt = int(1)
+		fullInt = 1
 	}
-	url := fmt.Sprintf("%s/api2/json/nodes/%s/qemu?full=%d", c.baseURL, node, fullInt)
+	path := fmt.Sprintf("%s/%s/qemu?full=%d", apiNodesPath, url.PathEscape(node), fullInt)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	// Attach auth
-	if c.authMethod == AuthToken {
-		req.Header.Set("Authorization", fmt.Sprintf("PVEAPIToken=%s=%s", c.username, c.password))
-	} else {
-		// If using ticket/session-based auth
-		req.Header.Set("Cookie", fmt.Sprintf("PVEAuthCookie=%s", c.authTicket))
-		req.Header.Set("CSRFPreventionToken", c.csrfToken)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request error: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result struct {
-		Data []QemuVm `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decoding data: %w", err)
+	var vms []QemuVm
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, false, &vms); err != nil {
+		return nil, err
 	}
-
-	return result.Data, nil
+	return vms, nil
 }